@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"escort/database"
+	"escort/models"
+	"escort/services"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,6 +20,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// maxSearchLength caps the admin user search input
+const maxSearchLength = 100
+
 // Helper function to calculate growth rate
 func calculateGrowthRate(current, previous int64) float64 {
 	if previous == 0 {
@@ -24,6 +31,15 @@ func calculateGrowthRate(current, previous int64) float64 {
 	return (float64(current-previous) / float64(previous)) * 100
 }
 
+// userImageURLs collects every photo URL of a user
+func userImageURLs(images []models.ProfileImage) []string {
+	var urls []string
+	for _, image := range images {
+		urls = append(urls, image.URLs()...)
+	}
+	return urls
+}
+
 // GetAllUsers - Get all users with filters and pagination
 func GetAllUsers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -57,13 +73,19 @@ func GetAllUsers(c *gin.Context) {
 		filter["role"] = role
 	}
 
-	if search != "" {
+	if search = strings.TrimSpace(search); search != "" {
+		// Match the input literally; it must never be interpreted as a pattern
+		// Cut by characters before quoting, so neither a letter nor an escape is split
+		if runes := []rune(search); len(runes) > maxSearchLength {
+			search = string(runes[:maxSearchLength])
+		}
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
 		filter["$or"] = []bson.M{
-			{"first_name": bson.M{"$regex": search, "$options": "i"}},
-			{"last_name": bson.M{"$regex": search, "$options": "i"}},
-			{"email": bson.M{"$regex": search, "$options": "i"}},
-			{"phone_no": bson.M{"$regex": search, "$options": "i"}},
-			{"location": bson.M{"$regex": search, "$options": "i"}},
+			{"first_name": bson.M{"$regex": pattern}},
+			{"last_name": bson.M{"$regex": pattern}},
+			{"email": bson.M{"$regex": pattern}},
+			{"phone_no": bson.M{"$regex": pattern}},
+			{"location": bson.M{"$regex": pattern}},
 		}
 	}
 
@@ -277,7 +299,9 @@ func DeleteUser(c *gin.Context) {
 	defer cancel()
 
 	// First check if user exists
-	var user bson.M
+	var user struct {
+		Images []models.ProfileImage `bson:"images"`
+	}
 	err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -293,11 +317,28 @@ func DeleteUser(c *gin.Context) {
 
 	// Also delete user's subscriptions
 	database.SubscriptionCollection.DeleteMany(ctx, bson.M{"user_id": userObjID})
+	database.SavedSearchCollection.DeleteMany(ctx, bson.M{"user_id": userObjID})
+	database.ImageHashCollection.DeleteMany(ctx, bson.M{"user_id": userObjID})
+
+	// And the stored photos; anything missed here is picked up by the cleanup job
+	imagesDeleted := 0
+	if storage, err := services.NewObjectStore(services.ProfileBucket()); err == nil {
+		imagesDeleted = services.DeleteObjectsByURL(ctx, storage, userImageURLs(user.Images))
+	}
+	if originals, err := services.NewObjectStore(services.PrivateBucket()); err == nil {
+		for _, image := range user.Images {
+			if image.OriginalKey != "" {
+				originals.Delete(ctx, image.OriginalKey)
+			}
+		}
+		deleteUserVerifications(ctx, originals, userObjID)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":       true,
-		"message":       "User deleted successfully",
-		"deleted_count": result.DeletedCount,
+		"success":        true,
+		"message":        "User deleted successfully",
+		"deleted_count":  result.DeletedCount,
+		"images_deleted": imagesDeleted,
 	})
 }
 
@@ -0,0 +1,219 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// boostOptionRequest creates or edits a boost price
+type boostOptionRequest struct {
+	Location      *string  `json:"location"` // Location ID or slug; empty for the default price
+	Amount        *float64 `json:"amount"`
+	DurationHours *int     `json:"duration_hours"`
+	IsActive      *bool    `json:"is_active"`
+}
+
+// findLocation looks a location up by ID or slug
+func findLocation(ctx context.Context, value string) (*models.Location, error) {
+	filter := bson.M{"keys": strings.ToLower(strings.TrimSpace(value))}
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		filter = bson.M{"_id": id}
+	}
+
+	var location models.Location
+	err := database.LocationCollection.FindOne(ctx, filter,
+		options.FindOne().SetSort(bson.D{{Key: "level", Value: 1}}),
+	).Decode(&location)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// GetBoostOptions - GET /admin/boost-options?location=nairobi
+// Lists boost prices, optionally those of one location ("default" for the fallback prices).
+func GetBoostOptions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	switch location := c.Query("location"); location {
+	case "":
+	case "default":
+		filter["location_id"] = nil
+	default:
+		place, err := findLocation(ctx, location)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Location not found"})
+			return
+		}
+		filter["location_id"] = place.ID
+	}
+
+	cursor, err := database.BoostOptionCollection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "location_label", Value: 1}, {Key: "duration_hours", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch boost options"})
+		return
+	}
+	boostOptions := []models.BoostOption{}
+	if err := cursor.All(ctx, &boostOptions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read boost options"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(boostOptions),
+		"data":    boostOptions,
+	})
+}
+
+// CreateBoostOption - POST /admin/boost-options {"location", "amount", "duration_hours"}
+// Once a location has prices, providers there (and in the places inside it) see only those.
+func CreateBoostOption(c *gin.Context) {
+	var request boostOptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Amount == nil || request.DurationHours == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount and duration_hours are required"})
+		return
+	}
+	if *request.Amount <= 0 || *request.DurationHours <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount and duration_hours must be positive"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	option := models.BoostOption{
+		ID:            primitive.NewObjectID(),
+		Amount:        *request.Amount,
+		DurationHours: *request.DurationHours,
+		IsActive:      request.IsActive == nil || *request.IsActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if request.Location != nil && strings.TrimSpace(*request.Location) != "" {
+		place, err := findLocation(ctx, *request.Location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Location not found"})
+			return
+		}
+		option.LocationID = &place.ID
+		option.LocationLabel = place.Label
+	}
+
+	// One price per duration and location
+	conflict := bson.M{"location_id": nil, "duration_hours": option.DurationHours}
+	if option.LocationID != nil {
+		conflict["location_id"] = *option.LocationID
+	}
+	if count, _ := database.BoostOptionCollection.CountDocuments(ctx, conflict); count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A boost of this duration already exists for this location"})
+		return
+	}
+
+	if _, err := database.BoostOptionCollection.InsertOne(ctx, option); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create boost option"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Boost option created",
+		"data":    option,
+	})
+}
+
+// UpdateBoostOption - PUT /admin/boost-options/:id {"amount", "duration_hours", "is_active"}
+// Boosts already paid for keep the price and duration they were bought at.
+func UpdateBoostOption(c *gin.Context) {
+	optionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boost option ID"})
+		return
+	}
+
+	var request boostOptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	update := bson.M{"updated_at": time.Now()}
+	if request.Amount != nil {
+		if *request.Amount <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be positive"})
+			return
+		}
+		update["amount"] = *request.Amount
+	}
+	if request.DurationHours != nil {
+		if *request.DurationHours <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duration_hours must be positive"})
+			return
+		}
+		update["duration_hours"] = *request.DurationHours
+	}
+	if request.IsActive != nil {
+		update["is_active"] = *request.IsActive
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var option models.BoostOption
+	err = database.BoostOptionCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": optionID},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&option)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Boost option not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Boost option updated",
+		"data":    option,
+	})
+}
+
+// DeleteBoostOption - DELETE /admin/boost-options/:id
+func DeleteBoostOption(c *gin.Context) {
+	optionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boost option ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.BoostOptionCollection.DeleteOne(ctx, bson.M{"_id": optionID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete boost option"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Boost option not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Boost option deleted",
+	})
+}
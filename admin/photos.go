@@ -0,0 +1,327 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/notifications"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxModerationBatch    = 100
+	maxRejectReasonLength = 500
+	originalURLExpiry     = 15 * time.Minute
+)
+
+// photoModerationRequest selects photos for a bulk approve or reject
+type photoModerationRequest struct {
+	Items []struct {
+		UserID  string `json:"user_id"`
+		ImageID string `json:"image_id"`
+	} `json:"items" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// GetPhotoQueue - GET /admin/photos?status=pending&flagged=true&page=&limit=
+func GetPhotoQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ImageStatusPending)
+
+	var statusFilter interface{}
+	switch status {
+	case models.ImageStatusPending, models.ImageStatusRejected:
+		statusFilter = status
+	case models.ImageStatusApproved:
+		// Photos from before moderation have no status and count as approved
+		statusFilter = bson.M{"$in": bson.A{models.ImageStatusApproved, nil}}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved or rejected"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Oldest pending photos first so nothing waits forever
+	sortOrder := 1
+	if status != models.ImageStatusPending {
+		sortOrder = -1
+	}
+
+	photoFilter := bson.M{"images.status": statusFilter}
+	// ?flagged=true narrows the queue to suspected duplicates of other accounts' photos
+	if c.Query("flagged") == "true" {
+		photoFilter["images.duplicates.0"] = bson.M{"$exists": true}
+	}
+
+	// Suspected duplicates are reviewed first
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"images": bson.M{"$exists": true, "$ne": bson.A{}}}},
+		bson.M{"$unwind": "$images"},
+		bson.M{"$match": photoFilter},
+		bson.M{"$addFields": bson.M{"flagged": bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$images.duplicates", bson.A{}}}}, 0}}}},
+		bson.M{"$sort": bson.D{{Key: "flagged", Value: -1}, {Key: "images.created_at", Value: sortOrder}, {Key: "_id", Value: 1}}},
+		bson.M{"$facet": bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"items": bson.A{
+				bson.M{"$skip": (page - 1) * limit},
+				bson.M{"$limit": limit},
+				bson.M{"$project": bson.M{
+					"_id":        0,
+					"user_id":    "$_id",
+					"first_name": 1,
+					"last_name":  1,
+					"location":   1,
+					"is_active":  1,
+					"image":      "$images",
+				}},
+			},
+		}},
+	}
+
+	cursor, err := database.UserCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch photo queue"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Items []struct {
+			UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+			FirstName string              `bson:"first_name" json:"first_name"`
+			LastName  string              `bson:"last_name" json:"last_name"`
+			Location  string              `bson:"location" json:"location"`
+			IsActive  bool                `bson:"is_active" json:"is_active"`
+			Image     models.ProfileImage `bson:"image" json:"image"`
+		} `bson:"items"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read photo queue"})
+		return
+	}
+
+	// Moderators review the unwatermarked originals through short-lived links
+	originals, storeErr := services.NewObjectStore(services.PrivateBucket())
+
+	var total int64
+	items := []gin.H{}
+	if len(results) > 0 {
+		if len(results[0].Total) > 0 {
+			total = results[0].Total[0].Count
+		}
+		for _, item := range results[0].Items {
+			entry := gin.H{
+				"user_id":    item.UserID,
+				"full_name":  item.FirstName + " " + item.LastName,
+				"location":   item.Location,
+				"is_active":  item.IsActive,
+				"image":      item.Image,
+				"flagged":    len(item.Image.Duplicates) > 0,
+				"duplicates": duplicateMatches(item.Image.Duplicates),
+			}
+			if storeErr == nil && item.Image.OriginalKey != "" {
+				if signedURL, err := originals.SignedURL(ctx, item.Image.OriginalKey, originalURLExpiry); err == nil {
+					entry["original_url"] = signedURL
+				}
+			}
+			items = append(items, entry)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"status":  status,
+		"photos":  items,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// duplicateMatches describes the photos an image duplicates, with links to the owning profiles
+func duplicateMatches(duplicates []models.ImageDuplicate) []gin.H {
+	matches := []gin.H{}
+	for _, duplicate := range duplicates {
+		matches = append(matches, gin.H{
+			"user_id":     duplicate.UserID,
+			"image_id":    duplicate.ImageID,
+			"distance":    duplicate.Distance,
+			"admin_url":   "/admin/users/" + duplicate.UserID.Hex(),
+			"profile_url": "/user/" + duplicate.UserID.Hex(),
+		})
+	}
+	return matches
+}
+
+// ApprovePhotos - POST /admin/photos/approve {"items": [{"user_id", "image_id"}]}
+func ApprovePhotos(c *gin.Context) {
+	moderatePhotos(c, models.ImageStatusApproved)
+}
+
+// RejectPhotos - POST /admin/photos/reject {"items": [...], "reason": "..."}
+func RejectPhotos(c *gin.Context) {
+	moderatePhotos(c, models.ImageStatusRejected)
+}
+
+// moderatePhotos sets the status of a batch of photos and refreshes each
+// affected user's public cover photo
+func moderatePhotos(c *gin.Context, status string) {
+	var request photoModerationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "items is required"})
+		return
+	}
+	if len(request.Items) == 0 || len(request.Items) > maxModerationBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Select between 1 and %d photos", maxModerationBatch)})
+		return
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if status == models.ImageStatusRejected && reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A rejection reason is required"})
+		return
+	}
+	if len(reason) > maxRejectReasonLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Reason must be at most %d characters", maxRejectReasonLength)})
+		return
+	}
+
+	// Group the photos by owner so each user is updated once
+	imagesByUser := map[primitive.ObjectID][]primitive.ObjectID{}
+	var userOrder []primitive.ObjectID
+	for _, item := range request.Items {
+		userObjID, err := primitive.ObjectIDFromHex(item.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID: " + item.UserID})
+			return
+		}
+		imageObjID, err := primitive.ObjectIDFromHex(item.ImageID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID: " + item.ImageID})
+			return
+		}
+		if _, ok := imagesByUser[userObjID]; !ok {
+			userOrder = append(userOrder, userObjID)
+		}
+		imagesByUser[userObjID] = append(imagesByUser[userObjID], imageObjID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"images.$[img].status":       status,
+		"images.$[img].moderated_at": now,
+		"images.$[img].moderated_by": c.GetString("userID"),
+		"updated_at":                 now,
+	}
+	update := bson.M{"$set": set}
+	if status == models.ImageStatusRejected {
+		set["images.$[img].rejection_reason"] = reason
+	} else {
+		update["$unset"] = bson.M{"images.$[img].rejection_reason": ""}
+	}
+
+	updated := 0
+	failed := []string{}
+
+	for _, userObjID := range userOrder {
+		imageIDs := imagesByUser[userObjID]
+
+		updateOptions := options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"img.id": bson.M{"$in": imageIDs}}},
+		})
+		result, err := database.UserCollection.UpdateOne(ctx,
+			bson.M{"_id": userObjID, "images.id": bson.M{"$in": imageIDs}},
+			update,
+			updateOptions,
+		)
+		if err != nil || result.MatchedCount == 0 {
+			failed = append(failed, userObjID.Hex())
+			continue
+		}
+		updated += len(imageIDs)
+
+		if err := refreshCoverImage(ctx, userObjID); err != nil {
+			fmt.Printf("⚠️ Could not refresh cover photo of user %s: %v\n", userObjID.Hex(), err)
+		}
+
+		if status == models.ImageStatusRejected {
+			notifyPhotosRejected(ctx, userObjID, imageIDs, reason)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      len(failed) == 0,
+		"status":       status,
+		"updated":      updated,
+		"failed_users": failed,
+	})
+}
+
+// refreshCoverImage points image_url at the user's approved cover photo
+func refreshCoverImage(ctx context.Context, userObjID primitive.ObjectID) error {
+	var user struct {
+		Images         []models.ProfileImage `bson:"images"`
+		PrimaryImageID *primitive.ObjectID   `bson:"primary_image_id"`
+	}
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		return err
+	}
+
+	_, err := database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$set": bson.M{"image_url": models.CoverImageURL(user.Images, user.PrimaryImageID)}},
+	)
+	return err
+}
+
+// notifyPhotosRejected tells a user which of their photos were rejected and why
+func notifyPhotosRejected(ctx context.Context, userObjID primitive.ObjectID, imageIDs []primitive.ObjectID, reason string) {
+	message := "One of your photos was rejected: " + reason
+	if len(imageIDs) > 1 {
+		message = fmt.Sprintf("%d of your photos were rejected: %s", len(imageIDs), reason)
+	}
+
+	ids := make([]string, 0, len(imageIDs))
+	for _, id := range imageIDs {
+		ids = append(ids, id.Hex())
+	}
+
+	err := notifications.Send(ctx, userObjID, notifications.TypePhotoRejected, "Photo rejected", message, map[string]interface{}{
+		"image_ids": ids,
+		"reason":    reason,
+	})
+	if err != nil {
+		fmt.Printf("⚠️ Could not notify user %s about rejected photos: %v\n", userObjID.Hex(), err)
+	}
+}
@@ -0,0 +1,254 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// serviceRequest creates or edits a catalogue entry
+type serviceRequest struct {
+	Name     *string  `json:"name"`
+	Category *string  `json:"category"`
+	Aliases  []string `json:"aliases"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// GetServices - GET /admin/services
+// Lists the whole catalogue, including inactive services, with how many profiles use each.
+func GetServices(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.ServiceCollection.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var services []models.Service
+	if err := cursor.All(ctx, &services); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read services"})
+		return
+	}
+
+	// Profile counts per service name
+	usage := map[string]int{}
+	usageCursor, err := database.UserCollection.Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"role": "user", "services.0": bson.M{"$exists": true}}},
+		bson.M{"$unwind": "$services"},
+		bson.M{"$group": bson.M{"_id": "$services", "count": bson.M{"$sum": 1}}},
+	})
+	if err == nil {
+		var counts []struct {
+			Name  string `bson:"_id"`
+			Count int    `bson:"count"`
+		}
+		if usageCursor.All(ctx, &counts) == nil {
+			for _, count := range counts {
+				usage[count.Name] = count.Count
+			}
+		}
+	}
+
+	items := []gin.H{}
+	for _, service := range services {
+		items = append(items, gin.H{
+			"service":  service,
+			"profiles": usage[service.Name],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"count":    len(items),
+		"services": items,
+	})
+}
+
+// CreateService - POST /admin/services {"name", "category", "aliases"}
+func CreateService(c *gin.Context) {
+	var request serviceRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Name == nil || request.Category == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and category are required"})
+		return
+	}
+
+	name := strings.TrimSpace(*request.Name)
+	category := strings.TrimSpace(*request.Category)
+	slug := models.Slugify(name)
+	if slug == "" || category == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and category are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	keys := database.ServiceKeys(name, slug, request.Aliases)
+	if conflict := serviceKeyConflict(ctx, keys, primitive.NilObjectID); conflict != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": conflict})
+		return
+	}
+
+	now := time.Now()
+	service := models.Service{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		Slug:      slug,
+		Category:  category,
+		Aliases:   request.Aliases,
+		Keys:      keys,
+		IsActive:  request.IsActive == nil || *request.IsActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := database.ServiceCollection.InsertOne(ctx, service); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Service created",
+		"service": service,
+	})
+}
+
+// UpdateService - PUT /admin/services/:id {"name", "category", "aliases", "is_active"}
+// Renaming a service renames it on every profile offering it. Inactive services
+// stay on profiles but can no longer be picked.
+func UpdateService(c *gin.Context) {
+	serviceID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	var request serviceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var service models.Service
+	if err := database.ServiceCollection.FindOne(ctx, bson.M{"_id": serviceID}).Decode(&service); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+	oldName := service.Name
+
+	if request.Name != nil {
+		service.Name = strings.TrimSpace(*request.Name)
+		service.Slug = models.Slugify(service.Name)
+		if service.Slug == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name cannot be empty"})
+			return
+		}
+	}
+	if request.Category != nil {
+		if service.Category = strings.TrimSpace(*request.Category); service.Category == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category cannot be empty"})
+			return
+		}
+	}
+	if request.Aliases != nil {
+		service.Aliases = request.Aliases
+	}
+	if request.IsActive != nil {
+		service.IsActive = *request.IsActive
+	}
+	if service.Name != oldName {
+		service.Aliases = renamedServiceAliases(service.Aliases, oldName, service.Name)
+	}
+
+	service.Keys = database.ServiceKeys(service.Name, service.Slug, service.Aliases)
+	if conflict := serviceKeyConflict(ctx, service.Keys, serviceID); conflict != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": conflict})
+		return
+	}
+
+	service.UpdatedAt = time.Now()
+	_, err = database.ServiceCollection.UpdateOne(ctx, bson.M{"_id": serviceID}, bson.M{"$set": bson.M{
+		"name":       service.Name,
+		"slug":       service.Slug,
+		"category":   service.Category,
+		"aliases":    service.Aliases,
+		"keys":       service.Keys,
+		"is_active":  service.IsActive,
+		"updated_at": service.UpdatedAt,
+	}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+		return
+	}
+
+	var renamed int64
+	if service.Name != oldName {
+		result, err := database.UserCollection.UpdateMany(ctx,
+			bson.M{"services": oldName},
+			bson.M{"$set": bson.M{"services.$[s]": service.Name}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"s": oldName}}}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Service renamed but profiles could not be updated"})
+			return
+		}
+		renamed = result.ModifiedCount
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"message":          "Service updated",
+		"service":          service,
+		"profiles_renamed": renamed,
+	})
+}
+
+// renamedServiceAliases keeps the old name of a renamed service as an alias, so
+// searches and links using it still find the service, and drops the new name
+// from the aliases when a service gets a former name back
+func renamedServiceAliases(aliases []string, oldName, newName string) []string {
+	kept := []string{}
+	hasOldName := false
+	for _, alias := range aliases {
+		if strings.EqualFold(strings.TrimSpace(alias), newName) {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(alias), oldName) {
+			hasOldName = true
+		}
+		kept = append(kept, alias)
+	}
+	if !hasOldName && !strings.EqualFold(oldName, newName) {
+		kept = append(kept, oldName)
+	}
+	return kept
+}
+
+// serviceKeyConflict describes which other service already uses one of keys, if any
+func serviceKeyConflict(ctx context.Context, keys []string, exceptID primitive.ObjectID) string {
+	var other models.Service
+	err := database.ServiceCollection.FindOne(ctx, bson.M{
+		"_id":  bson.M{"$ne": exceptID},
+		"keys": bson.M{"$in": keys},
+	}).Decode(&other)
+	if err != nil {
+		return ""
+	}
+	return "The name or an alias is already used by " + other.Name
+}
@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenamedServiceAliases(t *testing.T) {
+	tests := []struct {
+		name             string
+		aliases          []string
+		oldName, newName string
+		want             []string
+	}{
+		{"old name becomes an alias", []string{"massages"}, "Massage", "Body Massage", []string{"massages", "Massage"}},
+		{"no aliases yet", nil, "Incall", "In Call", []string{"Incall"}},
+		{"old name already an alias", []string{"massage"}, "Massage", "Body Massage", []string{"massage"}},
+		{"former name taken back", []string{"nuru", "Nuru Massage"}, "Nuru", "Nuru Massage", []string{"nuru"}},
+		{"case change only", []string{"video calls"}, "Video call", "Video Call", []string{"video calls"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renamedServiceAliases(tt.aliases, tt.oldName, tt.newName); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renamedServiceAliases(%q, %q, %q) = %q, want %q", tt.aliases, tt.oldName, tt.newName, got, tt.want)
+			}
+		})
+	}
+}
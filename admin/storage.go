@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"escort/jobs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollectOrphanedImages - Run the orphaned photo cleanup now (?dry_run=true to only report)
+func CollectOrphanedImages(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	report, err := jobs.CollectOrphanedImages(ctx, c.Query("dry_run") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Image cleanup failed: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"report":  report,
+	})
+}
@@ -0,0 +1,354 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/notifications"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetVerificationQueue - GET /admin/verifications?status=pending&type=&page=&limit=
+// Each submission comes with the profile photos and date of birth it should be compared against.
+func GetVerificationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", models.VerificationPending)
+	switch status {
+	case models.VerificationPending, models.VerificationApproved, models.VerificationRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved or rejected"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"status": status}
+	switch verificationType := c.Query("type"); verificationType {
+	case "":
+	case models.VerificationTypeSelfie, models.VerificationTypeIDDocument:
+		filter["type"] = verificationType
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be selfie or id_document"})
+		return
+	}
+	total, err := database.VerificationCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count verifications"})
+		return
+	}
+
+	// Oldest submissions first so nothing waits forever
+	sortOrder := 1
+	if status != models.VerificationPending {
+		sortOrder = -1
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "submitted_at", Value: sortOrder}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.VerificationCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch verifications"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var verifications []models.Verification
+	if err := cursor.All(ctx, &verifications); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read verifications"})
+		return
+	}
+
+	// Load every submitter in one query
+	userIDs := make([]primitive.ObjectID, 0, len(verifications))
+	for _, verification := range verifications {
+		userIDs = append(userIDs, verification.UserID)
+	}
+	users := map[primitive.ObjectID]models.User{}
+	if len(userIDs) > 0 {
+		userCursor, err := database.UserCollection.Find(ctx,
+			bson.M{"_id": bson.M{"$in": userIDs}},
+			options.Find().SetProjection(bson.M{"password": 0}),
+		)
+		if err == nil {
+			var found []models.User
+			if err := userCursor.All(ctx, &found); err == nil {
+				for _, user := range found {
+					users[user.ID] = user
+				}
+			}
+		}
+	}
+
+	private, storeErr := services.NewObjectStore(services.PrivateBucket())
+	signedURL := func(key string) string {
+		if storeErr != nil || key == "" {
+			return ""
+		}
+		url, err := private.SignedURL(ctx, key, originalURLExpiry)
+		if err != nil {
+			return ""
+		}
+		return url
+	}
+
+	items := []gin.H{}
+	for _, verification := range verifications {
+		entry := gin.H{
+			"verification": verification,
+			"selfie_url":   signedURL(verification.FileKey),
+		}
+
+		if user, ok := users[verification.UserID]; ok {
+			photos := []gin.H{}
+			for _, image := range user.Images {
+				photos = append(photos, gin.H{
+					"image":        image,
+					"original_url": signedURL(image.OriginalKey),
+				})
+			}
+			entry["user"] = gin.H{
+				"id":              user.ID,
+				"full_name":       user.FirstName + " " + user.LastName,
+				"email":           user.Email,
+				"location":        user.Location,
+				"is_active":       user.IsActive,
+				"verified_at":     user.VerifiedAt,
+				"date_of_birth":   user.DateOfBirth,
+				"age_verified_at": user.AgeVerifiedAt,
+				"admin_url":       "/admin/users/" + user.ID.Hex(),
+			}
+			entry["profile_photos"] = photos
+		}
+
+		items = append(items, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"status":        status,
+		"verifications": items,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// ApproveVerification - POST /admin/verifications/:id/approve {"date_of_birth": "YYYY-MM-DD"}
+// For ID documents date_of_birth optionally corrects the claimed date to the one on the document.
+func ApproveVerification(c *gin.Context) {
+	var request struct {
+		DateOfBirth string `json:"date_of_birth"`
+	}
+	c.ShouldBindJSON(&request)
+
+	now := time.Now()
+	var correctedDOB *time.Time
+	if request.DateOfBirth != "" {
+		dob, err := models.ParseDateOfBirth(request.DateOfBirth, now)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error() + "; reject the document instead"})
+			return
+		}
+		correctedDOB = &dob
+	}
+
+	verification, ok := reviewVerification(c, models.VerificationApproved, "")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set := bson.M{"updated_at": now}
+	title, message := "Profile verified", "Your profile is now verified and shows the verified badge"
+	if verification.Type == models.VerificationTypeIDDocument {
+		dateOfBirth := verification.DateOfBirth
+		if correctedDOB != nil {
+			dateOfBirth = correctedDOB
+		}
+		if dateOfBirth == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date_of_birth is required"})
+			return
+		}
+		set["age_verified_at"] = now
+		set["date_of_birth"] = *dateOfBirth
+		set["age"] = models.AgeOn(*dateOfBirth, now)
+		title, message = "Age verified", "Your age is confirmed and your profile can now appear in listings"
+	} else {
+		set["verified_at"] = now
+	}
+
+	_, err := database.UserCollection.UpdateOne(ctx, bson.M{"_id": verification.UserID}, bson.M{"$set": set})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark user as verified"})
+		return
+	}
+
+	err = notifications.Send(ctx, verification.UserID, notifications.TypeVerificationApproved, title, message,
+		map[string]interface{}{"verification_id": verification.ID.Hex(), "type": verification.Type})
+	if err != nil {
+		fmt.Printf("⚠️ Could not notify user %s about their verification: %v\n", verification.UserID.Hex(), err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "Verification approved",
+		"verification": verification,
+		"verified_at":  now,
+	})
+}
+
+// RejectVerification - POST /admin/verifications/:id/reject {"reason": "..."}
+func RejectVerification(c *gin.Context) {
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	c.ShouldBindJSON(&request)
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A rejection reason is required"})
+		return
+	}
+	if len(reason) > maxRejectReasonLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Reason must be at most %d characters", maxRejectReasonLength)})
+		return
+	}
+
+	verification, ok := reviewVerification(c, models.VerificationRejected, reason)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	message := "Your verification selfie was rejected: " + reason + ". You can request a new code and try again."
+	if verification.Type == models.VerificationTypeIDDocument {
+		message = "Your ID document was rejected: " + reason + ". You can submit a new one."
+	}
+
+	err := notifications.Send(ctx, verification.UserID, notifications.TypeVerificationRejected, "Verification rejected", message,
+		map[string]interface{}{"verification_id": verification.ID.Hex(), "type": verification.Type, "reason": reason})
+	if err != nil {
+		fmt.Printf("⚠️ Could not notify user %s about their verification: %v\n", verification.UserID.Hex(), err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "Verification rejected",
+		"verification": verification,
+	})
+}
+
+// reviewVerification moves a pending verification to status, writing the error
+// response itself when it can't
+func reviewVerification(c *gin.Context, status, reason string) (*models.Verification, bool) {
+	verificationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid verification ID"})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"status":      status,
+		"reviewed_by": c.GetString("userID"),
+		"reviewed_at": now,
+		"updated_at":  now,
+	}
+	if reason != "" {
+		set["rejection_reason"] = reason
+	}
+
+	var verification models.Verification
+	err = database.VerificationCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": verificationID, "status": models.VerificationPending},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&verification)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending verification not found"})
+		return nil, false
+	}
+	return &verification, true
+}
+
+// RevokeVerification - DELETE /admin/users/:id/verification
+func RevokeVerification(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$unset": bson.M{"verified_at": ""}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke verification"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Verification revoked",
+	})
+}
+
+// deleteUserVerifications removes a deleted user's verification records and photos
+func deleteUserVerifications(ctx context.Context, private services.ObjectStore, userObjID primitive.ObjectID) {
+	cursor, err := database.VerificationCollection.Find(ctx, bson.M{"user_id": userObjID})
+	if err != nil {
+		return
+	}
+	var verifications []models.Verification
+	if err := cursor.All(ctx, &verifications); err != nil {
+		return
+	}
+
+	for _, verification := range verifications {
+		if verification.FileKey != "" {
+			private.Delete(ctx, verification.FileKey)
+		}
+	}
+	database.VerificationCollection.DeleteMany(ctx, bson.M{"user_id": userObjID})
+}
@@ -2,12 +2,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"escort/database"
+	"escort/middleware"
 	"escort/models"
 	"escort/services"
 	"os"
@@ -53,9 +54,10 @@ func RegisterUser(c *gin.Context) {
 		Password          string   `json:"password" binding:"required"`
 		Gender            string   `json:"gender" binding:"required"`
 		SexualOrientation string   `json:"sexualOrientation"`
-		Age               int      `json:"age"`
+		DateOfBirth       string   `json:"dateOfBirth" binding:"required"` // YYYY-MM-DD
 		Nationality       string   `json:"nationality"`
-		Location          string   `json:"location" binding:"required"`
+		LocationID        string   `json:"locationId"` // From GET /locations
+		Location          string   `json:"location"`   // Name, slug or alias when no locationId is sent
 		Services          []string `json:"services"`
 	}
 
@@ -68,9 +70,44 @@ func RegisterUser(c *gin.Context) {
 		return
 	}
 
+	// Only adults may register
+	now := time.Now()
+	dateOfBirth, err := models.ParseDateOfBirth(req.DateOfBirth, now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Profiles must point at a place of the location taxonomy
+	locationValue := req.LocationID
+	if locationValue == "" {
+		locationValue = req.Location
+	}
+	location, err := ResolveLocation(context.TODO(), locationValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Unknown location. Choose one from /locations",
+		})
+		return
+	}
+
+	// Services come from the catalogue
+	services, err := NormalizeServices(context.TODO(), req.Services)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	// Check if user exists
 	var existingUser models.User
-	err := database.UserCollection.FindOne(context.TODO(), bson.M{"email": req.Email}).Decode(&existingUser)
+	err = database.UserCollection.FindOne(context.TODO(), bson.M{"email": req.Email}).Decode(&existingUser)
 	if err == nil {
 		c.JSON(http.StatusConflict, gin.H{
 			"success": false,
@@ -99,12 +136,17 @@ func RegisterUser(c *gin.Context) {
 		Email:              req.Email,
 		PhoneNo:            req.PhoneNo,
 		Password:           string(hashedPassword),
-		Location:           req.Location,
+		Location:           location.Label,
+		LocationID:         &location.ID,
+		LocationPath:       location.Path,
+		GeoLocation:        location.Center,
+		GeoSource:          models.GeoSourceArea,
 		Gender:             req.Gender,
 		SexualOrientation:  req.SexualOrientation,
-		Age:                req.Age,
+		Age:                models.AgeOn(dateOfBirth, now),
+		DateOfBirth:        &dateOfBirth,
 		Nationality:        req.Nationality,
-		Services:           req.Services,
+		Services:           services,
 		IsActive:           false,
 		Role:               "user",
 		HasSubscription:    false,
@@ -168,6 +210,7 @@ func LoginUser(c *gin.Context) {
 	//Generate JWT Token
 
 	token := generateJWT(user.ID.Hex(), user.Role)
+	middleware.RecordActivity(user.ID.Hex())
 
 	c.JSON(http.StatusOK, gin.H{"token": token, "role": user.Role, "id": user.ID.Hex()})
 }
@@ -183,275 +226,155 @@ func generateJWT(userID string, role string) string {
 	return tokenString
 }
 
-// Get all Active Users
-func GetAllActiveUsers(c *gin.Context) {
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// NEW LOGIC: Get users who are EITHER:
-	// 1. Admin-approved (is_active: true) OR
-	// 2. Have active subscription (has_subscription: true AND subscription_expiry > now)
-	filter := bson.M{
-		"role": "user",
+// visibleProfileFilter matches profiles that may appear publicly. Users must have had
+// their age confirmed by an admin and are visible if they are EITHER:
+// 1. Admin-approved (is_active: true) OR
+// 2. Have active subscription (has_subscription: true AND subscription_expiry > now)
+func visibleProfileFilter(now time.Time) bson.M {
+	return bson.M{
+		"role":            "user",
+		"age_verified_at": bson.M{"$ne": nil},
 		"$or": []bson.M{
 			// Option 1: Admin-approved users
 			{"is_active": true},
 			// Option 2: Users with active subscription
 			{
 				"has_subscription":    true,
-				"subscription_expiry": bson.M{"$gt": time.Now()},
+				"subscription_expiry": bson.M{"$gt": now},
 			},
 		},
 	}
+}
 
-	location := c.Query("location")
-	if location != "" {
-		filter["location"] = bson.M{"$regex": location, "$options": "i"}
-	}
-
-	// Find options - get the fields we need
-	findOptions := options.Find()
-	findOptions.SetLimit(50)
+// PublicProfileFilter matches the profile id only if visibleProfileFilter lets it
+// appear publicly, so profiles hidden from listings can't be fetched by ID either
+func PublicProfileFilter(id primitive.ObjectID, now time.Time) bson.M {
+	filter := visibleProfileFilter(now)
+	filter["_id"] = id
+	return filter
+}
 
-	// UPDATE: Add has_subscription to projection
-	projection := bson.M{
-		"first_name":       1,
-		"last_name":        1,
-		"phone_no":         1,
-		"image_url":        1,
-		"location":         1,
-		"services":         1,
-		"has_subscription": 1, // ADD THIS
+// listingError answers a failed listing the same way for every public listing endpoint
+func listingError(c *gin.Context, err error) {
+	if errors.Is(err, ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
 	}
-	findOptions.SetProjection(projection)
+	fmt.Printf("❌ Listing failed: %v\n", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch users from database"})
+}
 
-	// Execute the query
-	cursor, err := database.UserCollection.Find(ctx, filter, findOptions)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch users from database"})
-		return
+// listingPagination describes the page of a listing in the shape clients already use.
+// Infinite scroll should follow next_cursor rather than page numbers, which shift
+// as profiles register.
+func listingPagination(query ListingQuery, page ListingPage) gin.H {
+	return gin.H{
+		"current_page": page.Page,
+		"per_page":     query.Limit,
+		"total":        page.Total,
+		"total_pages":  page.TotalPages(query.Limit),
+		"has_next":     page.HasMore,
+		"has_prev":     page.Page > 1,
 	}
-	defer cursor.Close(ctx)
+}
 
-	// Read results
-	var results []bson.M
-	if err = cursor.All(ctx, &results); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to read user data"})
+// Get all Active Users
+// GET /users?location=&service=&gender=&min_age=&max_age=&nationality=&orientation=&verified=&sort=&page=&limit=&cursor=
+func GetAllActiveUsers(c *gin.Context) {
+	query, err := parseListingQuery(c, 50)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
 		return
 	}
 
-	// Convert to Minimal User Response
-	var users []models.MinimalUserResponse
-	for _, result := range results {
-		// Safely extract fields with type assertions
-		id, _ := result["_id"].(primitive.ObjectID)
-		firstName, _ := result["first_name"].(string)
-		lastName, _ := result["last_name"].(string)
-		phoneNo, _ := result["phone_no"].(string)
-		imageUrl, _ := result["image_url"].(string)
-		location, _ := result["location"].(string)
-
-		// NEW: Get has_subscription field
-		hasSubscription, _ := result["has_subscription"].(bool)
-
-		// Handle services
-		var services []string
-		switch s := result["services"].(type) {
-		case primitive.A:
-			for _, v := range s {
-				if str, ok := v.(string); ok {
-					services = append(services, str)
-				}
-			}
-		case string:
-			if s != "" {
-				services = strings.Split(s, ",")
-				for i, service := range services {
-					services[i] = strings.TrimSpace(service)
-				}
-			}
-		case []interface{}:
-			for _, v := range s {
-				if str, ok := v.(string); ok {
-					services = append(services, str)
-				}
-			}
-		case []string:
-			services = s
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		user := models.MinimalUserResponse{
-			ID:              id,
-			FullName:        firstName + " " + lastName,
-			PhoneNo:         phoneNo,
-			ImageUrl:        imageUrl,
-			Services:        services,
-			Location:        location,
-			HasSubscription: hasSubscription, // ADD THIS
-		}
-		users = append(users, user)
+	page, err := RunListing(ctx, query)
+	if err != nil {
+		listingError(c, err)
+		return
 	}
 
 	// Log for debugging
-	fmt.Printf("✅ Found %d visible users (admin-approved or subscribed)\n", len(users))
+	fmt.Printf("✅ Found %d visible users (admin-approved or subscribed)\n", len(page.Users))
 
 	// Return successful response
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"count":   len(users),
-		"data":    users,
-		"message": "Users fetched successfully",
+		"success":     true,
+		"count":       len(page.Users),
+		"data":        page.Users,
+		"pagination":  listingPagination(query, page),
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+		"message":     "Users fetched successfully",
 	})
 }
 
-// search users by location or services
+// Search users
+// GET /search?q=&location=&service=&gender=&min_age=&max_age=&nationality=&orientation=&verified=&sort=&page=&limit=&cursor=&facets=true
+// q matches names, locations and services by word prefix and tolerates small typos;
+// results are ordered by relevance (featured profiles boosted) unless sort is given.
+// With facets=true the response also counts the matches per location, service, gender, age and verified badge.
 func SearchUsers(c *gin.Context) {
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Get search query from URL parameter
-	query := c.Query("q")
-	location := c.Query("location")
-	service := c.Query("service")
-
-	// Validate at least one search parameter is provided
-	if query == "" && location == "" && service == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "At least one search parameter (q, location, service) is required"})
-		return
-	}
-
-	// Build filter based on provided parameters
-	filter := bson.M{
-		"role":      "user",
-		"is_active": true,
-	}
-
-	//search conditions
-	var searchConditions []bson.M
-
-	// If query parameter in multiple fileds
-	if query != "" {
-		searchConditions = append(searchConditions, bson.M{
-			"$or": []bson.M{
-				{"location": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-				{"first_name": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-				{"last_name": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-				{"services": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-			},
-		})
-	}
-
-	// If specific location parameter provided
-	if location != "" {
-		searchConditions = append(searchConditions, bson.M{
-			"location": bson.M{"$regex": primitive.Regex{Pattern: location, Options: "i"}},
-		})
-	}
-
-	// If service parameter provided
-	if service != "" {
-		searchConditions = append(searchConditions, bson.M{
-			"services": bson.M{"$in": []string{service}},
-		})
-	}
-
-	// Combine all search conditions with AND
-	if len(searchConditions) > 0 {
-		filter["$and"] = searchConditions
-	}
-
-	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	skip := (page - 1) * limit
-
-	// Find options for pagination
-	findOptions := options.Find()
-	findOptions.SetSkip(int64(skip))
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}}) // Newest first
-
-	// Get total count for pagination
-	totalCount, err := database.UserCollection.CountDocuments(ctx, filter)
+	query, err := parseListingQuery(c, 10)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to count search results",
-		})
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
 		return
 	}
+	withFacets := c.Query("facets") == "true"
 
-	// Execute query
-	cursor, err := database.UserCollection.Find(ctx, filter, findOptions)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to search plumbers",
-		})
+	// Validate at least one search parameter is provided; facets alone describe every profile
+	if !query.HasCriteria() && !withFacets {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "At least one search parameter (q, location, service, gender, min_age, max_age, nationality, orientation, verified) is required"})
 		return
 	}
-	defer cursor.Close(ctx)
 
-	// Read results
-	var users []models.User
-	if err = cursor.All(ctx, &users); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to decode search results",
-		})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	page, err := RunListing(ctx, query)
+	if err != nil {
+		listingError(c, err)
 		return
 	}
 
-	//convert to Minimal User Response
-	var results []models.MinimalUserResponse
-	for _, user := range users {
-		minUser := models.MinimalUserResponse{
-			ID:       user.ID,
-			FullName: user.FirstName + " " + user.LastName,
-			PhoneNo:  user.PhoneNo,
-			ImageUrl: user.ImageUrl,
-			Services: user.Services,
-			Location: user.Location,
+	var facets *SearchFacets
+	if withFacets {
+		if facets, err = SearchFacetCounts(ctx, query); err != nil {
+			listingError(c, err)
+			return
 		}
-		results = append(results, minUser)
-	}
-
-	//calculate total pages
-	totalPages := 0
-	if totalCount > 0 {
-		totalPages = int((totalCount + int64(limit) - 1) / int64(limit))
-
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    results,
-		"pagination": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total":        totalCount,
-			"total_pages":  totalPages,
-			"has_next":     page < totalPages,
-			"has_prev":     page > 1,
-		},
+		"success":     true,
+		"data":        page.Users,
+		"pagination":  listingPagination(query, page),
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+		"facets":      facets,
 		"search_query": gin.H{
-			"q":        query,
-			"location": location,
-			"service":  service,
+			"q":           query.Text,
+			"location":    query.Location,
+			"service":     query.Services,
+			"gender":      query.Gender,
+			"min_age":     query.MinAge,
+			"max_age":     query.MaxAge,
+			"nationality": query.Nationality,
+			"orientation": query.Orientation,
+			"verified":    query.Verified,
+			"sort":        query.Sort,
 		},
 		"message": "Search completed successfully",
 	})
 }
 
 // Get user by specific Location
+// GET /location/:location, accepting the same filters as /users. A county or town
+// slug includes every area inside it, so /location/nairobi covers all of Nairobi.
 func GetUsersByLocation(c *gin.Context) {
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	location := c.Param("location")
+	location := strings.TrimSpace(c.Param("location"))
 	if location == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
@@ -460,63 +383,31 @@ func GetUsersByLocation(c *gin.Context) {
 		return
 	}
 
-	// Build filter
-	filter := bson.M{
-		"role":      "user",
-		"is_active": true,
-		"location":  bson.M{"$regex": primitive.Regex{Pattern: location, Options: "i"}},
-	}
-
-	// Get pagination
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	skip := (page - 1) * limit
-
-	findOptions := options.Find()
-	findOptions.SetSkip(int64(skip))
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}})
-
-	// Execute query
-	cursor, err := database.UserCollection.Find(ctx, filter, findOptions)
+	query, err := parseListingQuery(c, 10)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to fetch users by location",
-		})
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
 		return
 	}
-	defer cursor.Close(ctx)
+	query.Location = location
 
-	var users []models.User
-	if err = cursor.All(ctx, &users); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to decode users",
-		})
-		return
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Convert to response
-	var results []models.MinimalUserResponse
-	for _, user := range users {
-		minUser := models.MinimalUserResponse{
-			ID:       user.ID,
-			FullName: user.FirstName + " " + user.LastName,
-			PhoneNo:  user.PhoneNo,
-			ImageUrl: user.ImageUrl,
-			Services: user.Services,
-			Location: user.Location,
-		}
-		results = append(results, minUser)
+	page, err := RunListing(ctx, query)
+	if err != nil {
+		listingError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"data":     users,
-		"location": location,
-		"count":    len(users),
-		"message":  "Users in " + location + " retrieved successfully",
+		"success":     true,
+		"data":        page.Users,
+		"location":    location,
+		"count":       len(page.Users),
+		"pagination":  listingPagination(query, page),
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+		"message":     "Users in " + location + " retrieved successfully",
 	})
 }
 
@@ -902,16 +793,20 @@ func MpesaCallback(c *gin.Context) {
 
 // processSuccessfulPayment handles successful MPESA payments
 func processSuccessfulPayment(stkCallback map[string]interface{}) {
+	checkoutRequestID, _ := stkCallback["CheckoutRequestID"].(string)
+
 	// Extract metadata
 	callbackMetadata, ok := stkCallback["CallbackMetadata"].(map[string]interface{})
 	if !ok {
 		fmt.Println("❌ No CallbackMetadata in successful payment")
+		failBoost(checkoutRequestID, "Payment callback had no metadata")
 		return
 	}
 
 	items, ok := callbackMetadata["Item"].([]interface{})
 	if !ok {
 		fmt.Println("❌ No Items in CallbackMetadata")
+		failBoost(checkoutRequestID, "Payment callback had no metadata")
 		return
 	}
 
@@ -956,8 +851,10 @@ func processSuccessfulPayment(stkCallback map[string]interface{}) {
 	fmt.Printf("   Phone: %s\n", phoneNumber)
 	fmt.Printf("   Date: %s\n", transactionDate)
 
-	// Find subscription by checkout ID
-	checkoutRequestID, _ := stkCallback["CheckoutRequestID"].(string)
+	// Find the boost or subscription by checkout ID
+	if activateBoost(checkoutRequestID, mpesaReceiptNumber, amount) {
+		return
+	}
 	activateSubscription(checkoutRequestID, mpesaReceiptNumber, amount)
 }
 
@@ -1011,9 +908,11 @@ func activateSubscription(checkoutID, receiptNumber string, amount float64) {
 	_, err = database.UserCollection.UpdateOne(ctx,
 		bson.M{"_id": subscription.UserID},
 		bson.M{"$set": bson.M{
-			"has_subscription":    true,
-			"subscription_expiry": expiryDate, // Use calculated expiry
-			"last_payment_date":   time.Now(),
+			"has_subscription":     true,
+			"subscription_expiry":  expiryDate, // Use calculated expiry
+			"last_payment_date":    time.Now(),
+			"subscription_plan_id": plan.ID,
+			"subscription_rank":    plan.RankWeight,
 		}},
 	)
 
@@ -1037,6 +936,8 @@ func processFailedPayment(checkoutID, failureReason string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	failBoost(checkoutID, failureReason)
+
 	// Update subscription status to failed
 	_, err := database.SubscriptionCollection.UpdateOne(ctx,
 		bson.M{"checkout_id": checkoutID},
@@ -3,20 +3,196 @@ package controllers
 import (
 	"context"
 	"escort/models"
+	"fmt"
+	"log"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var blogCollection *mongo.Collection
 
+// maxSlugLength keeps generated slugs readable in URLs
+const maxSlugLength = 80
+
 func InitBlogCollection(client *mongo.Client) {
 	db := client.Database("NairobiEscort") // Replace "escort" with your actual DB name
 	blogCollection = db.Collection("blogs")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Slugs must be unique. Posts from before slugs were generated may share one
+	// or have none, which would stop the index from building.
+	if fixed, err := dedupeBlogSlugs(ctx); err != nil {
+		log.Fatalf("❌ Could not deduplicate blog slugs: %v", err)
+	} else if fixed > 0 {
+		fmt.Printf("✅ Gave %d blog posts a unique slug\n", fixed)
+	}
+	if _, err := blogCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		log.Fatalf("❌ Could not create the unique blog slug index: %v", err)
+	}
+
+	// Old slugs are indexed so renamed posts can redirect
+	blogIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "previous_slugs", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "published", Value: 1},
+				{Key: "published_at", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "category", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "keywords", Value: 1}},
+		},
+		{
+			// Full-text search, title matches weigh the most
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "description", Value: "text"},
+				{Key: "content", Value: "text"},
+				{Key: "keywords", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("blog_text_search").
+				SetDefaultLanguage("english").
+				SetWeights(bson.M{
+					"title":       10,
+					"keywords":    5,
+					"description": 3,
+					"content":     1,
+				}),
+		},
+	}
+
+	if _, err := blogCollection.Indexes().CreateMany(ctx, blogIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create blog indexes: %v", err)
+	} else {
+		fmt.Println("✅ Blog indexes created")
+	}
+
+	// Posts published before published_at existed sort by their creation date
+	_, err := blogCollection.UpdateMany(ctx,
+		bson.M{"published": true, "published_at": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"published_at": "$created_at"}}}},
+	)
+	if err != nil {
+		log.Printf("⚠️ Warning: Could not backfill blog publish dates: %v", err)
+	}
+}
+
+// dedupeBlogSlugs gives posts without a slug, or sharing one with an older post,
+// a unique slug derived from their title. It returns the number of posts changed.
+func dedupeBlogSlugs(ctx context.Context) (int, error) {
+	cursor, err := blogCollection.Find(ctx, bson.M{}, options.Find().
+		SetProjection(bson.M{"title": 1, "slug": 1}).
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	var posts []models.Blog
+	if err := cursor.All(ctx, &posts); err != nil {
+		return 0, err
+	}
+
+	// The oldest post keeps a shared slug
+	used := map[string]bool{}
+	fixed := 0
+	for _, post := range posts {
+		if post.Slug != "" && !used[post.Slug] {
+			used[post.Slug] = true
+			continue
+		}
+
+		base := post.Slug
+		if base == "" {
+			base = blogSlug(post.Title)
+		}
+		slug, err := uniqueSlug(ctx, base, post.ID)
+		if err != nil {
+			return fixed, err
+		}
+		if _, err := blogCollection.UpdateOne(ctx, bson.M{"_id": post.ID}, bson.M{"$set": bson.M{"slug": slug}}); err != nil {
+			return fixed, err
+		}
+		used[slug] = true
+		fixed++
+	}
+	return fixed, nil
+}
+
+// blogListSort orders published posts newest first, with _id as the tie-breaker
+var blogListSort = []sortKey{
+	{Field: "published_at", Desc: true},
+	{Field: "_id", Desc: true},
+}
+
+// exactMatch builds a case-insensitive equality match for user supplied text
+func exactMatch(value string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(value) + "$", "$options": "i"}
+}
+
+// blogSlug turns a title into a slug, shortened to keep post URLs readable
+func blogSlug(title string) string {
+	slug := models.Slugify(title)
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// slugTaken reports whether a slug is used (currently or previously) by another post
+func slugTaken(ctx context.Context, slug string, excludeID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"slug": slug},
+			{"previous_slugs": slug},
+		},
+	}
+	if !excludeID.IsZero() {
+		filter["_id"] = bson.M{"$ne": excludeID}
+	}
+
+	count, err := blogCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// uniqueSlug returns base, or base with a numeric suffix if it is already taken
+func uniqueSlug(ctx context.Context, base string, excludeID primitive.ObjectID) (string, error) {
+	if base == "" {
+		base = "post"
+	}
+
+	candidate := base
+	for i := 2; ; i++ {
+		taken, err := slugTaken(ctx, candidate, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
 }
 
 // CreateBlog - POST /api/blogs
@@ -30,38 +206,258 @@ func CreateBlog(c *gin.Context) {
 	blog.ID = primitive.NewObjectID()
 	blog.CreatedAt = time.Now()
 	blog.UpdatedAt = time.Now()
+	blog.PreviousSlugs = nil
+	if blog.Published {
+		blog.PublishedAt = blog.CreatedAt
+	} else {
+		blog.PublishedAt = time.Time{}
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	result, err := blogCollection.InsertOne(ctx, blog)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create blog"})
+	if err := resolveBlogImage(ctx, &blog, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cover image not found in media library"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"success": true, "data": result.InsertedID})
+	// Use the client slug if given, otherwise derive it from the title
+	base := blogSlug(blog.Slug)
+	if base == "" {
+		base = blogSlug(blog.Title)
+	}
+
+	// Retry on duplicate key in case another post claimed the slug concurrently
+	var result *mongo.InsertOneResult
+	for attempt := 0; attempt < 3; attempt++ {
+		slug, err := uniqueSlug(ctx, base, blog.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate slug"})
+			return
+		}
+		blog.Slug = slug
+
+		result, err = blogCollection.InsertOne(ctx, blog)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == 2 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create blog"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": result.InsertedID, "slug": blog.Slug})
 }
 
-// GetAllBlogs - GET /api/blogs
+// GetAllBlogs - GET /api/blogs?category=&keyword=&author=&limit=&cursor=
 func GetAllBlogs(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cursor, err := blogCollection.Find(ctx, bson.M{"published": true})
+	filter := bson.M{"published": true}
+
+	if category := strings.TrimSpace(c.Query("category")); category != "" {
+		filter["category"] = exactMatch(category)
+	}
+	if keyword := strings.TrimSpace(c.Query("keyword")); keyword != "" {
+		filter["keywords"] = exactMatch(keyword)
+	}
+	if author := strings.TrimSpace(c.Query("author")); author != "" {
+		filter["author"] = exactMatch(author)
+	}
+
+	limit := parseLimit(c, 10, 50)
+
+	if token := c.Query("cursor"); token != "" {
+		values, err := decodeCursor(token, len(blogListSort))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter = bson.M{"$and": []bson.M{filter, afterCursorFilter(blogListSort, values)}}
+	}
+
+	// Fetch one extra post to know whether another page exists
+	findOptions := options.Find().
+		SetSort(sortKeysDoc(blogListSort)).
+		SetLimit(int64(limit + 1)).
+		SetProjection(bson.M{"content": 0})
+
+	cursor, err := blogCollection.Find(ctx, filter, findOptions)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs"})
 		return
 	}
 	defer cursor.Close(ctx)
 
-	var blogs []models.Blog
+	blogs := []models.Blog{}
 	if err = cursor.All(ctx, &blogs); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse blogs"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": blogs})
+	hasMore := len(blogs) > limit
+	nextCursor := ""
+	if hasMore {
+		blogs = blogs[:limit]
+		last := blogs[len(blogs)-1]
+		nextCursor = encodeCursor(bson.A{last.PublishedAt, last.ID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        blogs,
+		"next_cursor": nextCursor,
+		"has_more":    hasMore,
+	})
+}
+
+// GetBlogCategories - GET /api/blogs/categories
+func GetBlogCategories(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"published": true, "category": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$category", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := blogCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Name  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse categories"})
+		return
+	}
+
+	categories := []gin.H{}
+	for _, result := range results {
+		categories = append(categories, gin.H{
+			"name":  result.Name,
+			"slug":  models.Slugify(result.Name),
+			"count": result.Count,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": categories})
+}
+
+// GetBlogTags - GET /api/blogs/tags
+func GetBlogTags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Keywords are grouped case-insensitively so "Nairobi" and "nairobi" count together
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"published": true}}},
+		{{Key: "$unwind", Value: "$keywords"}},
+		{{Key: "$match", Value: bson.M{"keywords": bson.M{"$ne": ""}}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"$toLower": "$keywords"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := blogCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Name  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse tags"})
+		return
+	}
+
+	tags := []gin.H{}
+	for _, result := range results {
+		tags = append(tags, gin.H{
+			"name":  result.Name,
+			"slug":  models.Slugify(result.Name),
+			"count": result.Count,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+// GetRelatedBlogs - GET /api/blogs/:slug/related
+func GetRelatedBlogs(c *gin.Context) {
+	slug := c.Param("slug")
+	limit := parseLimit(c, 4, 20)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var blog models.Blog
+	err := blogCollection.FindOne(ctx, bson.M{"slug": slug, "published": true}).Decode(&blog)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blog"})
+		return
+	}
+
+	keywords := blog.Keywords
+	if keywords == nil {
+		keywords = []string{}
+	}
+
+	// Rank candidates by shared keywords, then same category, then recency
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"published": true,
+			"_id":       bson.M{"$ne": blog.ID},
+			"$or": []bson.M{
+				{"keywords": bson.M{"$in": keywords}},
+				{"category": blog.Category},
+			},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"shared_keywords": bson.M{"$size": bson.M{"$setIntersection": bson.A{
+				bson.M{"$ifNull": bson.A{"$keywords", bson.A{}}},
+				keywords,
+			}}},
+			"same_category": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$category", blog.Category}}, 1, 0}},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "shared_keywords", Value: -1},
+			{Key: "same_category", Value: -1},
+			{Key: "published_at", Value: -1},
+		}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{"content": 0, "same_category": 0}}},
+	}
+
+	cursor, err := blogCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related blogs"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	related := []models.Blog{}
+	if err = cursor.All(ctx, &related); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse related blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": related})
 }
 
 // GetBlogBySlug - GET /api/blogs/:slug
@@ -73,6 +469,20 @@ func GetBlogBySlug(c *gin.Context) {
 
 	var blog models.Blog
 	err := blogCollection.FindOne(ctx, bson.M{"slug": slug, "published": true}).Decode(&blog)
+	if err == mongo.ErrNoDocuments {
+		// The post may have been renamed - point the client at the canonical slug
+		err = blogCollection.FindOne(ctx, bson.M{"previous_slugs": slug, "published": true}).Decode(&blog)
+		if err == nil {
+			c.Header("Location", "/api/blogs/"+blog.Slug)
+			c.JSON(http.StatusMovedPermanently, gin.H{
+				"success":        true,
+				"redirect":       true,
+				"canonical_slug": blog.Slug,
+				"data":           blog,
+			})
+			return
+		}
+	}
 	if err == mongo.ErrNoDocuments {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
 		return
@@ -100,18 +510,88 @@ func UpdateBlog(c *gin.Context) {
 		return
 	}
 
-	blog.UpdatedAt = time.Now()
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	result, err := blogCollection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": blog})
+	var existing models.Blog
+	err = blogCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&existing)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blog"})
+		return
+	}
+
+	blog.ID = objID
+	blog.CreatedAt = existing.CreatedAt
+	blog.UpdatedAt = time.Now()
+	blog.PreviousSlugs = existing.PreviousSlugs
+
+	// The publish date is set once, on the first publish
+	blog.PublishedAt = existing.PublishedAt
+	if blog.Published && blog.PublishedAt.IsZero() {
+		blog.PublishedAt = blog.UpdatedAt
+	}
+
+	if err := resolveBlogImage(ctx, &blog, &existing); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cover image not found in media library"})
+		return
+	}
+
+	// Keep the current slug unless the client asked for a different one
+	base := blogSlug(blog.Slug)
+	if base == "" {
+		base = existing.Slug
+	}
+
+	if base != existing.Slug {
+		slug, err := uniqueSlug(ctx, base, objID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate slug"})
+			return
+		}
+
+		// Remember the old slug for redirects; drop the new one if it was used before
+		var previous []string
+		for _, old := range append(existing.PreviousSlugs, existing.Slug) {
+			if old != slug && old != "" {
+				previous = append(previous, old)
+			}
+		}
+		blog.Slug = slug
+		blog.PreviousSlugs = previous
+	} else {
+		blog.Slug = existing.Slug
+	}
+
+	// Omitted optional fields must be cleared rather than left as they were
+	unset := bson.M{}
+	if len(blog.PreviousSlugs) == 0 {
+		unset["previous_slugs"] = ""
+	}
+	if blog.ImageID == nil {
+		unset["image_id"] = ""
+		unset["image_alt"] = ""
+	}
+
+	update := bson.M{"$set": blog}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := blogCollection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug already in use"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result.ModifiedCount})
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result.ModifiedCount, "slug": blog.Slug})
 }
 
 // DeleteBlog - DELETE /api/blogs/:id
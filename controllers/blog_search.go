@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSearchQueryLength = 200
+	snippetLength        = 200
+)
+
+var (
+	htmlTagPattern  = regexp.MustCompile(`<[^>]*>`)
+	markdownPattern = regexp.MustCompile("[#*_`>\\[\\]]+")
+	spacePattern    = regexp.MustCompile(`\s+`)
+)
+
+// blogSearchResult is a blog post with its relevance score and highlighted snippets
+type blogSearchResult struct {
+	models.Blog `bson:",inline"`
+	Score       float64 `bson:"score" json:"score"`
+}
+
+// SearchBlogs - GET /api/blogs/search?q=&limit=&page=
+func SearchBlogs(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query (q) is required"})
+		return
+	}
+	// Cut by characters so a multi-byte letter is never split
+	if runes := []rune(query); len(runes) > maxSearchQueryLength {
+		query = string(runes[:maxSearchQueryLength])
+	}
+
+	limit := parseLimit(c, 10, 50)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"$text":     bson.M{"$search": query},
+		"published": true,
+	}
+
+	total, err := blogCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search blogs"})
+		return
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "published_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := blogCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search blogs"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []blogSearchResult
+	if err = cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse search results"})
+		return
+	}
+
+	highlighter := newHighlighter(query)
+
+	data := []gin.H{}
+	for _, result := range results {
+		data = append(data, gin.H{
+			"id":           result.ID,
+			"title":        result.Title,
+			"slug":         result.Slug,
+			"description":  result.Description,
+			"category":     result.Category,
+			"author":       result.Author,
+			"image":        result.Image,
+			"keywords":     result.Keywords,
+			"published_at": blogPublishDate(result.Blog),
+			"score":        result.Score,
+			"highlights": gin.H{
+				"title":   highlighter.highlight(result.Title),
+				"snippet": highlighter.snippet(result.Content, result.Description),
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"query":   query,
+		"data":    data,
+		"pagination": gin.H{
+			"page":     page,
+			"limit":    limit,
+			"total":    total,
+			"has_more": int64(page*limit) < total,
+		},
+	})
+}
+
+// highlighter wraps search terms found in text with <mark> tags
+type highlighter struct {
+	pattern *regexp.Regexp
+}
+
+// newHighlighter builds a matcher for the query terms. Terms are reduced to a
+// rough stem so "escorts" in the query still marks "escort" in the text.
+func newHighlighter(query string) *highlighter {
+	var stems []string
+	seen := map[string]bool{}
+
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		stem := stemWord(word)
+		if len(stem) < 2 || seen[stem] {
+			continue
+		}
+		seen[stem] = true
+		stems = append(stems, regexp.QuoteMeta(stem))
+	}
+
+	if len(stems) == 0 {
+		return &highlighter{}
+	}
+	return &highlighter{pattern: regexp.MustCompile(`(?i)\b(?:` + strings.Join(stems, "|") + `)\w*`)}
+}
+
+// stemWord strips common English suffixes
+func stemWord(word string) string {
+	for _, suffix := range []string{"ing", "es", "ed", "s"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// highlight HTML-escapes text and marks every match
+func (h *highlighter) highlight(text string) string {
+	if h.pattern == nil {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range h.pattern.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:match[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		b.WriteString("</mark>")
+		last = match[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// snippet returns a highlighted excerpt around the first match in content,
+// falling back to the description when the content has no match
+func (h *highlighter) snippet(content, fallback string) string {
+	text := plainText(content)
+
+	start := 0
+	if h.pattern != nil {
+		if loc := h.pattern.FindStringIndex(text); loc != nil {
+			start = loc[0] - snippetLength/4
+		} else if fallback != "" {
+			text = plainText(fallback)
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	// Snap to word boundaries so the excerpt doesn't start or end mid-word
+	if start > 0 {
+		if space := strings.IndexByte(text[start:], ' '); space >= 0 {
+			start += space + 1
+		}
+	}
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+
+	end := start + snippetLength
+	if end >= len(text) {
+		end = len(text)
+	} else if space := strings.LastIndexByte(text[start:end], ' '); space > 0 {
+		end = start + space
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	excerpt := h.highlight(text[start:end])
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt
+}
+
+// plainText strips HTML tags and markdown markers from blog content
+func plainText(content string) string {
+	text := htmlTagPattern.ReplaceAllString(content, " ")
+	text = html.UnescapeString(text)
+	text = markdownPattern.ReplaceAllString(text, "")
+	return strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+}
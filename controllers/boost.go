@@ -0,0 +1,430 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/notifications"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// boostExpr is when a running boost was bought, the epoch without one, so boosted
+// profiles sort first with the latest bump on top. A boost bought for a location
+// only counts in listings of that location or a place containing it.
+func boostExpr(now time.Time, locationID *primitive.ObjectID) bson.M {
+	path := bson.M{"$ifNull": bson.A{"$boost_location_path", bson.A{}}}
+	applies := bson.A{bson.M{"$eq": bson.A{bson.M{"$size": path}, 0}}}
+	if locationID != nil {
+		applies = append(applies, bson.M{"$in": bson.A{*locationID, path}})
+	}
+
+	return bson.M{"$cond": bson.A{
+		bson.M{"$and": bson.A{
+			bson.M{"$gt": bson.A{"$boosted_until", now}},
+			bson.M{"$or": applies},
+		}},
+		bson.M{"$ifNull": bson.A{"$boosted_at", "$boosted_until"}},
+		time.Unix(0, 0),
+	}}
+}
+
+// boostOptionsFor lists the boosts on sale for a provider: those of their most
+// specific location that has any, otherwise the ones without a location
+func boostOptionsFor(ctx context.Context, locationPath []primitive.ObjectID) ([]models.BoostOption, error) {
+	candidates := bson.A{nil}
+	for _, id := range locationPath {
+		candidates = append(candidates, id)
+	}
+
+	cursor, err := database.BoostOptionCollection.Find(ctx,
+		bson.M{"is_active": true, "location_id": bson.M{"$in": candidates}},
+		options.Find().SetSort(bson.D{{Key: "duration_hours", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var all []models.BoostOption
+	if err := cursor.All(ctx, &all); err != nil {
+		return nil, err
+	}
+
+	// location_path runs from county to area, so walk it backwards
+	for i := len(locationPath) - 1; i >= 0; i-- {
+		var matching []models.BoostOption
+		for _, option := range all {
+			if option.LocationID != nil && *option.LocationID == locationPath[i] {
+				matching = append(matching, option)
+			}
+		}
+		if len(matching) > 0 {
+			return matching, nil
+		}
+	}
+
+	defaults := []models.BoostOption{}
+	for _, option := range all {
+		if option.LocationID == nil {
+			defaults = append(defaults, option)
+		}
+	}
+	return defaults, nil
+}
+
+// GetBoostOptions - GET /auth/boost/options
+// Lists the boost durations and prices for the provider's location.
+func GetBoostOptions(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "User not found"})
+		return
+	}
+
+	boostOptions, err := boostOptionsFor(ctx, user.LocationPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch boost options"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"data":          boostOptions,
+		"location":      user.Location,
+		"boosted_until": user.BoostedUntil,
+	})
+}
+
+// PurchaseBoost - POST /auth/boost {"option_id", "phone"}
+// Sends an MPESA STK push; the boost starts once the payment callback confirms it.
+func PurchaseBoost(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	var req struct {
+		OptionID string `json:"option_id" binding:"required"`
+		Phone    string `json:"phone,omitempty"` // Defaults to the profile's phone
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "option_id is required"})
+		return
+	}
+	optionID, err := primitive.ObjectIDFromHex(req.OptionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid option ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "User not found"})
+		return
+	}
+
+	// Only the options on sale for the provider's location can be bought
+	boostOptions, err := boostOptionsFor(ctx, user.LocationPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch boost options"})
+		return
+	}
+	var option *models.BoostOption
+	for i := range boostOptions {
+		if boostOptions[i].ID == optionID {
+			option = &boostOptions[i]
+		}
+	}
+	if option == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "This boost isn't available for your location"})
+		return
+	}
+
+	phone := strings.TrimSpace(req.Phone)
+	if phone == "" {
+		phone = user.PhoneNo
+	}
+	if phone == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Phone number is required"})
+		return
+	}
+
+	now := time.Now()
+	boost := models.Boost{
+		ID:            primitive.NewObjectID(),
+		UserID:        userObjID,
+		OptionID:      option.ID,
+		LocationID:    option.LocationID,
+		Amount:        option.Amount,
+		DurationHours: option.DurationHours,
+		Status:        models.BoostPending,
+		PhoneUsed:     phone,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if _, err := database.BoostCollection.InsertOne(ctx, boost); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to create boost"})
+		return
+	}
+
+	fmt.Printf("🚀 Initiating boost payment to: %s for KSH %.0f\n", phone, option.Amount)
+
+	mpesaResponse, err := services.NewMpesaService().InitiateSTKPush(
+		phone,
+		int(option.Amount),
+		fmt.Sprintf("BOOST-%s", boost.ID.Hex()),
+		fmt.Sprintf("Profile boost: %d hours", option.DurationHours),
+	)
+	if err != nil {
+		failPendingBoost(ctx, bson.M{"_id": boost.ID}, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to initiate MPESA payment: " + err.Error()})
+		return
+	}
+
+	// Without its checkout ID no callback can ever match the boost
+	checkoutID, ok := mpesaResponse["CheckoutRequestID"].(string)
+	if !ok || checkoutID == "" {
+		failPendingBoost(ctx, bson.M{"_id": boost.ID}, "MPESA response had no checkout ID")
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Invalid MPESA response"})
+		return
+	}
+	if _, err := database.BoostCollection.UpdateOne(ctx,
+		bson.M{"_id": boost.ID},
+		bson.M{"$set": bson.M{"checkout_id": checkoutID, "updated_at": time.Now()}},
+	); err != nil {
+		failPendingBoost(ctx, bson.M{"_id": boost.ID}, "Could not record the checkout ID")
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to update boost with checkout ID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        fmt.Sprintf("Payment initiated to %s. Check your phone to complete MPESA payment", maskPhone(phone)),
+		"boost_id":       boost.ID.Hex(),
+		"checkout_id":    checkoutID,
+		"amount":         option.Amount,
+		"duration_hours": option.DurationHours,
+		"phone_used":     maskPhone(phone),
+	})
+}
+
+// GetMyBoosts - GET /auth/boosts
+func GetMyBoosts(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.BoostCollection.Find(ctx, bson.M{"user_id": userObjID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(50))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch boosts"})
+		return
+	}
+	boosts := []models.Boost{}
+	if err := cursor.All(ctx, &boosts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to read boosts"})
+		return
+	}
+
+	var user models.User
+	database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID},
+		options.FindOne().SetProjection(bson.M{"boosted_until": 1})).Decode(&user)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"data":          boosts,
+		"boosted_until": user.BoostedUntil,
+	})
+}
+
+// checkBoostPayment reports whether a payment callback of amount should start
+// boost, and why the boost failed when it fails it. A boost that is no longer
+// pending was handled by an earlier callback.
+func checkBoostPayment(boost models.Boost, amount float64) (bool, string) {
+	if boost.Status != models.BoostPending {
+		return false, "" // MPESA retried the callback
+	}
+	if amount != boost.Amount {
+		return false, fmt.Sprintf("Paid KSH %.2f instead of %.2f", amount, boost.Amount)
+	}
+	return true, ""
+}
+
+// boostActivation is how a paid boost changes the provider's boost
+type boostActivation struct {
+	StartsAt     time.Time
+	EndsAt       time.Time
+	BoostedAt    time.Time
+	LocationPath []primitive.ObjectID // Empty when the boost applies everywhere
+}
+
+// planBoostActivation works out the boost user gets from boost, bought for the
+// place at locationPath (empty for everywhere). A boost bought while another runs
+// extends it, keeping its place in the order and the wider scope of the two.
+func planBoostActivation(user models.User, boost models.Boost, locationPath []primitive.ObjectID, now time.Time) boostActivation {
+	activation := boostActivation{StartsAt: now, BoostedAt: now, LocationPath: locationPath}
+	if user.BoostedUntil != nil && user.BoostedUntil.After(now) {
+		activation.StartsAt = *user.BoostedUntil
+		if user.BoostedAt != nil {
+			activation.BoostedAt = *user.BoostedAt
+		}
+		activation.LocationPath = widerBoostScope(user.BoostLocationPath, locationPath)
+	}
+	activation.EndsAt = activation.StartsAt.Add(time.Duration(boost.DurationHours) * time.Hour)
+	return activation
+}
+
+// widerBoostScope joins the places two boosts count in. Listings match a place
+// anywhere in the path, so the union counts wherever either did; an empty path
+// counts everywhere and wins.
+func widerBoostScope(current, next []primitive.ObjectID) []primitive.ObjectID {
+	if len(current) == 0 || len(next) == 0 {
+		return nil
+	}
+	scope := append([]primitive.ObjectID{}, current...)
+	for _, id := range next {
+		found := false
+		for _, existing := range scope {
+			if existing == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			scope = append(scope, id)
+		}
+	}
+	return scope
+}
+
+// activateBoost starts the boost paid through checkoutID. It reports whether the
+// checkout belonged to a boost, so the payment callback can fall back to subscriptions.
+func activateBoost(checkoutID, receiptNumber string, amount float64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if checkoutID == "" {
+		return false
+	}
+	var boost models.Boost
+	if err := database.BoostCollection.FindOne(ctx, bson.M{"checkout_id": checkoutID}).Decode(&boost); err != nil {
+		return false
+	}
+	proceed, failure := checkBoostPayment(boost, amount)
+	if failure != "" {
+		fmt.Printf("❌ Boost %s: %s\n", boost.ID.Hex(), failure)
+		failPendingBoost(ctx, bson.M{"_id": boost.ID}, failure)
+	}
+	if !proceed {
+		return true
+	}
+
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": boost.UserID}).Decode(&user); err != nil {
+		fmt.Printf("❌ User not found for boost %s\n", boost.ID.Hex())
+		return true
+	}
+
+	var locationPath []primitive.ObjectID
+	if boost.LocationID != nil {
+		var location models.Location
+		if err := database.LocationCollection.FindOne(ctx, bson.M{"_id": *boost.LocationID}).Decode(&location); err != nil {
+			fmt.Printf("❌ Location not found for boost %s: %v\n", boost.ID.Hex(), err)
+			return true
+		}
+		locationPath = location.Path
+	}
+
+	now := time.Now()
+	activation := planBoostActivation(user, boost, locationPath, now)
+	endsAt := activation.EndsAt
+
+	// Only the callback that moves the boost out of pending applies it
+	result, err := database.BoostCollection.UpdateOne(ctx,
+		bson.M{"_id": boost.ID, "status": models.BoostPending},
+		bson.M{"$set": bson.M{
+			"status":        models.BoostActive,
+			"mpesa_receipt": receiptNumber,
+			"starts_at":     activation.StartsAt,
+			"ends_at":       endsAt,
+			"updated_at":    now,
+		}},
+	)
+	if err != nil {
+		fmt.Printf("❌ Failed to activate boost: %v\n", err)
+		return true
+	}
+	if result.ModifiedCount != 1 {
+		return true
+	}
+
+	userUpdate := bson.M{"$set": bson.M{"boosted_at": activation.BoostedAt, "boosted_until": endsAt}}
+	if len(activation.LocationPath) > 0 {
+		userUpdate["$set"].(bson.M)["boost_location_path"] = activation.LocationPath
+	} else {
+		userUpdate["$unset"] = bson.M{"boost_location_path": ""}
+	}
+	if _, err := database.UserCollection.UpdateOne(ctx, bson.M{"_id": boost.UserID}, userUpdate); err != nil {
+		fmt.Printf("❌ Failed to boost user: %v\n", err)
+		return true
+	}
+
+	message := fmt.Sprintf("Your profile is at the top of listings until %s.", endsAt.Format("Jan 2, 15:04"))
+	if err := notifications.Send(ctx, boost.UserID, notifications.TypeBoostActivated, "Profile boosted", message,
+		map[string]interface{}{"boost_id": boost.ID.Hex(), "boosted_until": endsAt},
+	); err != nil {
+		fmt.Printf("⚠️ Could not notify user %s of boost: %v\n", boost.UserID.Hex(), err)
+	}
+
+	fmt.Printf("🚀 Boost %s active until %s\n", boost.ID.Hex(), endsAt.Format("2006-01-02 15:04"))
+	return true
+}
+
+// failBoost marks the boost paid through checkoutID as failed, if there is one
+func failBoost(checkoutID, failureReason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if checkoutID == "" {
+		return
+	}
+	failPendingBoost(ctx, bson.M{"checkout_id": checkoutID}, failureReason)
+}
+
+// failPendingBoost marks the pending boost matching filter as failed
+func failPendingBoost(ctx context.Context, filter bson.M, failureReason string) {
+	filter["status"] = models.BoostPending
+	if _, err := database.BoostCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"status":         models.BoostFailed,
+		"failure_reason": failureReason,
+		"updated_at":     time.Now(),
+	}}); err != nil {
+		fmt.Printf("❌ Failed to mark boost as failed: %v\n", err)
+	}
+}
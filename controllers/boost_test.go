@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCheckBoostPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		amount      float64
+		wantProceed bool
+		wantFailure bool
+	}{
+		{"paid in full", models.BoostPending, 500, true, false},
+		{"paid less", models.BoostPending, 499, false, true},
+		{"paid more", models.BoostPending, 1000, false, true},
+		{"callback retried after activation", models.BoostActive, 500, false, false},
+		{"callback retried after failure", models.BoostFailed, 500, false, false},
+		{"retried with another amount", models.BoostActive, 1, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proceed, failure := checkBoostPayment(models.Boost{Status: tt.status, Amount: 500}, tt.amount)
+			if proceed != tt.wantProceed || (failure != "") != tt.wantFailure {
+				t.Errorf("checkBoostPayment = %v, %q; want proceed %v, failure %v", proceed, failure, tt.wantProceed, tt.wantFailure)
+			}
+		})
+	}
+}
+
+func TestPlanBoostActivation(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	hoursFromNow := func(hours int) *time.Time {
+		at := now.Add(time.Duration(hours) * time.Hour)
+		return &at
+	}
+	kenya, nairobi, kilimani, mombasa := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	boost := models.Boost{DurationHours: 24}
+
+	tests := []struct {
+		name          string
+		user          models.User
+		locationPath  []primitive.ObjectID
+		wantStarts    time.Time
+		wantBoostedAt time.Time
+		wantPath      []primitive.ObjectID
+	}{
+		{
+			"no boost running",
+			models.User{},
+			[]primitive.ObjectID{kenya, nairobi},
+			now, now, []primitive.ObjectID{kenya, nairobi},
+		},
+		{
+			"expired boost is replaced",
+			models.User{BoostedAt: hoursFromNow(-48), BoostedUntil: hoursFromNow(-1), BoostLocationPath: []primitive.ObjectID{kenya, mombasa}},
+			[]primitive.ObjectID{kenya, nairobi},
+			now, now, []primitive.ObjectID{kenya, nairobi},
+		},
+		{
+			"running boost is extended",
+			models.User{BoostedAt: hoursFromNow(-2), BoostedUntil: hoursFromNow(10)},
+			nil,
+			*hoursFromNow(10), *hoursFromNow(-2), nil,
+		},
+		{
+			"global boost keeps its scope",
+			models.User{BoostedAt: hoursFromNow(-2), BoostedUntil: hoursFromNow(10)},
+			[]primitive.ObjectID{kenya, nairobi},
+			*hoursFromNow(10), *hoursFromNow(-2), nil,
+		},
+		{
+			"global extension widens a local boost",
+			models.User{BoostedAt: hoursFromNow(-2), BoostedUntil: hoursFromNow(10), BoostLocationPath: []primitive.ObjectID{kenya, nairobi}},
+			nil,
+			*hoursFromNow(10), *hoursFromNow(-2), nil,
+		},
+		{
+			"narrower place keeps the wider scope",
+			models.User{BoostedAt: hoursFromNow(-2), BoostedUntil: hoursFromNow(10), BoostLocationPath: []primitive.ObjectID{kenya, nairobi, kilimani}},
+			[]primitive.ObjectID{kenya, nairobi},
+			*hoursFromNow(10), *hoursFromNow(-2), []primitive.ObjectID{kenya, nairobi, kilimani},
+		},
+		{
+			"deeper place adds its listings",
+			models.User{BoostedAt: hoursFromNow(-2), BoostedUntil: hoursFromNow(10), BoostLocationPath: []primitive.ObjectID{kenya, nairobi}},
+			[]primitive.ObjectID{kenya, nairobi, kilimani},
+			*hoursFromNow(10), *hoursFromNow(-2), []primitive.ObjectID{kenya, nairobi, kilimani},
+		},
+		{
+			"unrelated place counts in both",
+			models.User{BoostedAt: hoursFromNow(-2), BoostedUntil: hoursFromNow(10), BoostLocationPath: []primitive.ObjectID{kenya, nairobi}},
+			[]primitive.ObjectID{kenya, mombasa},
+			*hoursFromNow(10), *hoursFromNow(-2), []primitive.ObjectID{kenya, nairobi, mombasa},
+		},
+		{
+			"running boost without boosted_at",
+			models.User{BoostedUntil: hoursFromNow(10)},
+			nil,
+			*hoursFromNow(10), now, nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planBoostActivation(tt.user, boost, tt.locationPath, now)
+			if !got.StartsAt.Equal(tt.wantStarts) {
+				t.Errorf("starts at %s, want %s", got.StartsAt, tt.wantStarts)
+			}
+			if want := tt.wantStarts.Add(24 * time.Hour); !got.EndsAt.Equal(want) {
+				t.Errorf("ends at %s, want %s", got.EndsAt, want)
+			}
+			if !got.BoostedAt.Equal(tt.wantBoostedAt) {
+				t.Errorf("boosted at %s, want %s", got.BoostedAt, tt.wantBoostedAt)
+			}
+			if len(got.LocationPath) != 0 || len(tt.wantPath) != 0 {
+				if !reflect.DeepEqual(got.LocationPath, tt.wantPath) {
+					t.Errorf("location path %v, want %v", got.LocationPath, tt.wantPath)
+				}
+			}
+		})
+	}
+}
+
+func TestWiderBoostScopeDoesNotAlias(t *testing.T) {
+	current := make([]primitive.ObjectID, 2, 4)
+	current[0], current[1] = primitive.NewObjectID(), primitive.NewObjectID()
+	next := []primitive.ObjectID{current[0], primitive.NewObjectID()}
+
+	scope := widerBoostScope(current, next)
+	if len(scope) != 3 {
+		t.Fatalf("scope %v, want 3 places", scope)
+	}
+	if current[:cap(current)][2] != (primitive.ObjectID{}) {
+		t.Error("widerBoostScope appended into the caller's path")
+	}
+}
@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/notifications"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RegisterClient - POST /auth/register/client
+// Clients browse and save searches; they have no public profile, so they need no approval.
+func RegisterClient(c *gin.Context) {
+	var req struct {
+		FirstName   string `json:"firstName" binding:"required"`
+		LastName    string `json:"lastName" binding:"required"`
+		Email       string `json:"email" binding:"required,email"`
+		PhoneNo     string `json:"phoneNo"`
+		Password    string `json:"password" binding:"required"`
+		DateOfBirth string `json:"dateOfBirth" binding:"required"` // YYYY-MM-DD
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	// Only adults may register
+	now := time.Now()
+	dateOfBirth, err := models.ParseDateOfBirth(req.DateOfBirth, now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if !isValidPassword(req.Password) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Password must be at least 6 characters, with one uppercase letter and one special character",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if count, _ := database.UserCollection.CountDocuments(ctx, bson.M{"email": req.Email}); count > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "User already exists",
+		})
+		return
+	}
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(req.Password), 14)
+
+	user := models.User{
+		ID:          primitive.NewObjectID(),
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Email:       req.Email,
+		PhoneNo:     req.PhoneNo,
+		Password:    string(hashedPassword),
+		Age:         models.AgeOn(dateOfBirth, now),
+		DateOfBirth: &dateOfBirth,
+		IsActive:    true,
+		Role:        "client",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := database.UserCollection.InsertOne(ctx, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to register user",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Client account created. You can sign in now.",
+		"user": gin.H{
+			"id":        user.ID.Hex(),
+			"firstName": user.FirstName,
+			"lastName":  user.LastName,
+			"email":     user.Email,
+			"role":      user.Role,
+			"isActive":  user.IsActive,
+		},
+	})
+}
+
+// telegramLinkLifetime is how long a Telegram link token can be redeemed
+const telegramLinkLifetime = 15 * time.Minute
+
+// hashTelegramLinkToken is the form a link token is stored in
+func hashTelegramLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// StartTelegramLink - POST /auth/client/telegram
+// Returns a t.me link that opens the bot with a one-time token. Pressing Start
+// sends the token to TelegramWebhook, which links the chat it came from.
+func StartTelegramLink(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	botUsername := strings.TrimPrefix(os.Getenv("TELEGRAM_BOT_USERNAME"), "@")
+	if botUsername == "" || os.Getenv("TELEGRAM_BOT_TOKEN") == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "Telegram notifications are not configured"})
+		return
+	}
+
+	token, err := services.RandomKeySegment()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to create link"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expiresAt := time.Now().Add(telegramLinkLifetime)
+	if _, err := database.UserCollection.UpdateOne(ctx, bson.M{"_id": userObjID}, bson.M{"$set": bson.M{
+		"telegram_link_token_hash": hashTelegramLinkToken(token),
+		"telegram_link_expires_at": expiresAt,
+		"updated_at":               time.Now(),
+	}}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to create link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"link":       fmt.Sprintf("https://t.me/%s?start=%s", botUsername, token),
+		"expires_at": expiresAt,
+		"message":    "Open the link and press Start to receive notifications on Telegram",
+	})
+}
+
+// UnlinkTelegram - DELETE /auth/client/telegram
+// Stops Telegram notifications; they fall back to the inbox.
+func UnlinkTelegram(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := database.UserCollection.UpdateOne(ctx, bson.M{"_id": userObjID}, bson.M{
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"telegram_chat_id": "", "telegram_link_token_hash": "", "telegram_link_expires_at": ""},
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to unlink Telegram"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Telegram unlinked"})
+}
+
+// TelegramWebhook - POST /api/telegram/webhook
+// Receives the bot's updates. Telegram sends TELEGRAM_WEBHOOK_SECRET in the
+// X-Telegram-Bot-Api-Secret-Token header, set when registering the webhook.
+func TelegramWebhook(c *gin.Context) {
+	secret := os.Getenv("TELEGRAM_WEBHOOK_SECRET")
+	if secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "Telegram webhook is not configured"})
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Telegram-Bot-Api-Secret-Token")), []byte(secret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Invalid secret token"})
+		return
+	}
+
+	var update struct {
+		Message *struct {
+			Text string `json:"text"`
+			Chat struct {
+				ID   int64  `json:"id"`
+				Type string `json:"type"`
+			} `json:"chat"`
+		} `json:"message"`
+	}
+	// Anything but a 200 makes Telegram resend the update
+	if err := c.ShouldBindJSON(&update); err != nil || update.Message == nil || update.Message.Chat.Type != "private" {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+		return
+	}
+	command, token, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	token = strings.TrimSpace(token)
+	if command != "/start" || token == "" {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+	reply := models.User{TelegramChatID: chatID}
+	message := notifications.Message{
+		Title: "Telegram linked",
+		Body:  "You'll get your notifications here.",
+	}
+
+	var user models.User
+	err := database.UserCollection.FindOneAndUpdate(ctx,
+		bson.M{
+			"telegram_link_token_hash": hashTelegramLinkToken(token),
+			"telegram_link_expires_at": bson.M{"$gt": now},
+		},
+		bson.M{
+			"$set":   bson.M{"telegram_chat_id": chatID, "updated_at": now},
+			"$unset": bson.M{"telegram_link_token_hash": "", "telegram_link_expires_at": ""},
+		},
+	).Decode(&user)
+	if err != nil {
+		message = notifications.Message{
+			Title: "Link expired",
+			Body:  "This link is no longer valid. Request a new one from your account settings.",
+		}
+	} else {
+		fmt.Printf("🔗 Linked Telegram chat for user %s\n", user.ID.Hex())
+	}
+
+	if notifier, ok := notifications.Lookup(notifications.ChannelTelegram); ok && notifier.Ready(reply) == nil {
+		if err := notifier.Notify(ctx, reply, message); err != nil {
+			fmt.Printf("⚠️ Could not reply to Telegram chat: %v\n", err)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	facetCacheTTL     = time.Minute
+	facetCacheMaxSize = 500
+	facetLimit        = 30 // Values returned per facet, most common first
+)
+
+// ageBuckets are the age ranges counted by the age facet, youngest first
+var ageBuckets = []struct {
+	Label          string
+	MinAge, MaxAge int // MaxAge 0 means no upper bound
+}{
+	{"18-24", 18, 24},
+	{"25-29", 25, 29},
+	{"30-34", 30, 34},
+	{"35-39", 35, 39},
+	{"40-49", 40, 49},
+	{"50+", 50, 0},
+}
+
+// ageBucketBoundaries are the date of birth boundaries of the age buckets at now,
+// oldest first as $bucket wants them. Each but the last is the lower bound of a
+// bucket, from the oldest ageBuckets entry to the youngest.
+func ageBucketBoundaries(now time.Time) []time.Time {
+	boundaries := []time.Time{now.AddDate(-121, 0, 0)}
+	for i := len(ageBuckets) - 1; i >= 0; i-- {
+		maxAge := ageBuckets[i].MaxAge
+		if maxAge == 0 {
+			continue
+		}
+		boundaries = append(boundaries, now.AddDate(-(maxAge+1), 0, 0))
+	}
+	return append(boundaries, now.AddDate(-ageBuckets[0].MinAge, 0, 0).Add(time.Second))
+}
+
+// ageBucketOf maps the lower boundary a $bucket result is keyed by onto its
+// index in ageBuckets
+func ageBucketOf(boundaries []time.Time, lower primitive.DateTime) (int, bool) {
+	for i, boundary := range boundaries[:len(boundaries)-1] {
+		if boundary.UnixMilli() == int64(lower) {
+			return len(ageBuckets) - 1 - i, true
+		}
+	}
+	return 0, false
+}
+
+// FacetCount is the number of matching profiles with one value of a facet
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Label string `bson:"label,omitempty" json:"label,omitempty"`
+	Count int    `bson:"count" json:"count"`
+}
+
+// SearchFacets breaks the profiles matching a search down by the values clients can filter on
+type SearchFacets struct {
+	Counties  []FacetCount `json:"counties"`
+	Locations []FacetCount `json:"locations"`
+	Services  []FacetCount `json:"services"`
+	Gender    []FacetCount `json:"gender"`
+	Age       []FacetCount `json:"age"`
+	Verified  []FacetCount `json:"verified"`
+}
+
+// facetCache keeps recent facet counts so popular searches don't rerun the aggregation
+var facetCache = struct {
+	sync.Mutex
+	entries map[string]facetCacheEntry
+}{entries: map[string]facetCacheEntry{}}
+
+type facetCacheEntry struct {
+	facets    *SearchFacets
+	expiresAt time.Time
+}
+
+// facetCacheKey identifies the filters of a query; paging and sorting don't change
+// counts. The fields are JSON encoded, so no value can run into the next one.
+func (q ListingQuery) facetCacheKey() string {
+	services := make([]string, 0, len(q.Services))
+	for _, service := range q.Services {
+		services = append(services, strings.ToLower(service))
+	}
+	sort.Strings(services)
+
+	location := strings.ToLower(q.Location)
+	if q.locationID != nil {
+		location = q.locationID.Hex()
+	}
+
+	key, _ := json.Marshal([]interface{}{
+		strings.ToLower(q.Text), location, services,
+		strings.ToLower(q.Gender), strings.ToLower(q.Nationality), strings.ToLower(q.Orientation),
+		q.MinAge, q.MaxAge, q.Verified,
+	})
+	return string(key)
+}
+
+// SearchFacetCounts counts the profiles matching q per location, service, gender,
+// age bucket and verified badge. Results are cached for facetCacheTTL.
+func SearchFacetCounts(ctx context.Context, q ListingQuery) (*SearchFacets, error) {
+	if err := q.resolve(ctx); err != nil {
+		return nil, err
+	}
+
+	key := q.facetCacheKey()
+	now := time.Now()
+
+	facetCache.Lock()
+	if entry, ok := facetCache.entries[key]; ok && now.Before(entry.expiresAt) {
+		facetCache.Unlock()
+		return entry.facets, nil
+	}
+	facetCache.Unlock()
+
+	facets, err := aggregateFacets(ctx, q, now)
+	if err != nil {
+		return nil, err
+	}
+
+	facetCache.Lock()
+	if len(facetCache.entries) >= facetCacheMaxSize {
+		for k, entry := range facetCache.entries {
+			if now.After(entry.expiresAt) {
+				delete(facetCache.entries, k)
+			}
+		}
+		if len(facetCache.entries) >= facetCacheMaxSize {
+			facetCache.entries = map[string]facetCacheEntry{}
+		}
+	}
+	facetCache.entries[key] = facetCacheEntry{facets: facets, expiresAt: now.Add(facetCacheTTL)}
+	facetCache.Unlock()
+
+	return facets, nil
+}
+
+// aggregateFacets runs every facet in a single $facet pipeline over the listing filter
+func aggregateFacets(ctx context.Context, q ListingQuery, now time.Time) (*SearchFacets, error) {
+	topValues := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": facetLimit},
+		}
+	}
+
+	// Profiles are bucketed on their date of birth so ages never go stale
+	boundaries := ageBucketBoundaries(now)
+	bucketBoundaries := bson.A{}
+	for _, boundary := range boundaries {
+		bucketBoundaries = append(bucketBoundaries, boundary)
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": q.Filter(now)},
+		bson.M{"$facet": bson.M{
+			"counties": bson.A{
+				bson.M{"$match": bson.M{"location_path.0": bson.M{"$exists": true}}},
+				bson.M{"$group": bson.M{"_id": bson.M{"$arrayElemAt": bson.A{"$location_path", 0}}, "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}}},
+				bson.M{"$limit": facetLimit},
+			},
+			"locations": bson.A{
+				bson.M{"$match": bson.M{"location_id": bson.M{"$exists": true}}},
+				bson.M{"$group": bson.M{"_id": "$location_id", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}}},
+				bson.M{"$limit": facetLimit},
+			},
+			"services": append(bson.A{bson.M{"$unwind": "$services"}}, topValues("services")...),
+			"gender":   topValues("gender"),
+			"age": bson.A{
+				bson.M{"$match": bson.M{"date_of_birth": bson.M{"$type": "date"}}},
+				bson.M{"$bucket": bson.M{
+					"groupBy":    "$date_of_birth",
+					"boundaries": bucketBoundaries,
+					"default":    "other",
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}},
+			},
+			"verified": bson.A{
+				bson.M{"$group": bson.M{"_id": bson.M{"$gt": bson.A{"$verified_at", nil}}, "count": bson.M{"$sum": 1}}},
+			},
+		}},
+	}
+
+	cursor, err := database.UserCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Counties []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Count int                `bson:"count"`
+		} `bson:"counties"`
+		Locations []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Count int                `bson:"count"`
+		} `bson:"locations"`
+		Services []FacetCount `bson:"services"`
+		Gender   []FacetCount `bson:"gender"`
+		Age      []struct {
+			ID    interface{} `bson:"_id"`
+			Count int         `bson:"count"`
+		} `bson:"age"`
+		Verified []struct {
+			ID    bool `bson:"_id"`
+			Count int  `bson:"count"`
+		} `bson:"verified"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	facets := &SearchFacets{
+		Counties:  []FacetCount{},
+		Locations: []FacetCount{},
+		Services:  []FacetCount{},
+		Gender:    []FacetCount{},
+		Age:       []FacetCount{},
+		Verified:  []FacetCount{},
+	}
+	if len(results) == 0 {
+		return facets, nil
+	}
+	result := results[0]
+
+	// Locations are reported by slug, which is what the location filter accepts
+	var locationIDs []primitive.ObjectID
+	for _, county := range result.Counties {
+		locationIDs = append(locationIDs, county.ID)
+	}
+	for _, location := range result.Locations {
+		locationIDs = append(locationIDs, location.ID)
+	}
+	places := map[primitive.ObjectID]models.Location{}
+	if len(locationIDs) > 0 {
+		locationCursor, err := database.LocationCollection.Find(ctx, bson.M{"_id": bson.M{"$in": locationIDs}})
+		if err == nil {
+			var found []models.Location
+			if locationCursor.All(ctx, &found) == nil {
+				for _, place := range found {
+					places[place.ID] = place
+				}
+			}
+		}
+	}
+	for _, county := range result.Counties {
+		if place, ok := places[county.ID]; ok {
+			facets.Counties = append(facets.Counties, FacetCount{Value: place.Slug, Label: place.Label, Count: county.Count})
+		}
+	}
+	for _, location := range result.Locations {
+		if place, ok := places[location.ID]; ok {
+			facets.Locations = append(facets.Locations, FacetCount{Value: place.Slug, Label: place.Label, Count: location.Count})
+		}
+	}
+
+	facets.Services = append(facets.Services, result.Services...)
+	facets.Gender = append(facets.Gender, result.Gender...)
+
+	ageCounts := map[int]int{}
+	for _, bucket := range result.Age {
+		if lower, ok := bucket.ID.(primitive.DateTime); ok {
+			if i, ok := ageBucketOf(boundaries, lower); ok {
+				ageCounts[i] = bucket.Count
+			}
+		}
+	}
+	for i, bucket := range ageBuckets {
+		if count := ageCounts[i]; count > 0 {
+			facets.Age = append(facets.Age, FacetCount{Value: bucket.Label, Count: count})
+		}
+	}
+
+	for _, verified := range result.Verified {
+		facets.Verified = append(facets.Verified, FacetCount{Value: fmt.Sprint(verified.ID), Count: verified.Count})
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// bucketLabel puts dob in an age bucket the way $bucket does with boundaries and
+// maps the result back like GetFacets, "" for the default bucket
+func bucketLabel(boundaries []time.Time, dob time.Time) string {
+	value := primitive.NewDateTimeFromTime(dob)
+	for i := 0; i < len(boundaries)-1; i++ {
+		lower := primitive.NewDateTimeFromTime(boundaries[i])
+		upper := primitive.NewDateTimeFromTime(boundaries[i+1])
+		if value >= lower && value < upper {
+			if index, ok := ageBucketOf(boundaries, lower); ok {
+				return ageBuckets[index].Label
+			}
+			return "unmapped"
+		}
+	}
+	return ""
+}
+
+func TestAgeBucketBoundaries(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+	boundaries := ageBucketBoundaries(now)
+
+	if len(boundaries) != len(ageBuckets)+1 {
+		t.Fatalf("got %d boundaries for %d buckets", len(boundaries), len(ageBuckets))
+	}
+	for i := 1; i < len(boundaries); i++ {
+		if !boundaries[i].After(boundaries[i-1]) {
+			t.Fatalf("boundaries are not ascending at %d: %v", i, boundaries)
+		}
+	}
+
+	born := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		dob  time.Time
+		want string
+	}{
+		{"turns 18 tomorrow", born(2008, 10, 19), ""},
+		{"turns 18 today", born(2008, 10, 18), "18-24"},
+		{"turns 25 tomorrow", born(2001, 10, 19), "18-24"},
+		{"turns 25 today", born(2001, 10, 18), "25-29"},
+		{"29", born(1997, 1, 1), "25-29"},
+		{"turns 30 today", born(1996, 10, 18), "30-34"},
+		{"34", born(1991, 12, 31), "30-34"},
+		{"turns 35 today", born(1991, 10, 18), "35-39"},
+		{"turns 40 today", born(1986, 10, 18), "40-49"},
+		{"turns 50 tomorrow", born(1976, 10, 19), "40-49"},
+		{"turns 50 today", born(1976, 10, 18), "50+"},
+		{"120", born(1906, 10, 19), "50+"},
+		{"older than 120", born(1905, 1, 1), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bucketLabel(boundaries, tt.dob); got != tt.want {
+				t.Errorf("date of birth %s went to bucket %q, want %q", tt.dob.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgeBucketOfUnknownBoundary(t *testing.T) {
+	boundaries := ageBucketBoundaries(time.Now())
+
+	// The upper boundary closes the youngest bucket and keys none
+	if _, ok := ageBucketOf(boundaries, primitive.NewDateTimeFromTime(boundaries[len(boundaries)-1])); ok {
+		t.Error("the upper boundary mapped onto a bucket")
+	}
+	if _, ok := ageBucketOf(boundaries, primitive.NewDateTimeFromTime(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))); ok {
+		t.Error("a date between boundaries mapped onto a bucket")
+	}
+}
+
+func TestFacetCacheKey(t *testing.T) {
+	nairobi := primitive.NewObjectID()
+
+	same := []struct {
+		name string
+		a, b ListingQuery
+	}{
+		{"case", ListingQuery{Text: "Spa", Gender: "Female"}, ListingQuery{Text: "spa", Gender: "female"}},
+		{"service order", ListingQuery{Services: []string{"Massage", "Incall"}}, ListingQuery{Services: []string{"incall", "massage"}}},
+		{"paging ignored", ListingQuery{Text: "spa", Page: 1}, ListingQuery{Text: "spa", Page: 3}},
+		{"resolved location", ListingQuery{Location: "Nairobi", locationID: &nairobi}, ListingQuery{Location: "nairobi county", locationID: &nairobi}},
+	}
+	for _, tt := range same {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a.facetCacheKey() != tt.b.facetCacheKey() {
+				t.Errorf("keys differ: %s and %s", tt.a.facetCacheKey(), tt.b.facetCacheKey())
+			}
+		})
+	}
+
+	different := []struct {
+		name string
+		a, b ListingQuery
+	}{
+		{"separator in text", ListingQuery{Text: "a|b"}, ListingQuery{Text: "a", Location: "b"}},
+		{"separator in location", ListingQuery{Location: "x|", Gender: "y"}, ListingQuery{Location: "x", Gender: "|y"}},
+		{"comma in a service", ListingQuery{Services: []string{"a,b"}}, ListingQuery{Services: []string{"a", "b"}}},
+		{"quote in text", ListingQuery{Text: `a","b`}, ListingQuery{Text: "a", Location: "b"}},
+		{"ages", ListingQuery{MinAge: 2, MaxAge: 30}, ListingQuery{MinAge: 23, MaxAge: 0}},
+		{"verified", ListingQuery{Verified: true}, ListingQuery{}},
+	}
+	for _, tt := range different {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a.facetCacheKey() == tt.b.facetCacheKey() {
+				t.Errorf("both queries got the key %s", tt.a.facetCacheKey())
+			}
+		})
+	}
+}
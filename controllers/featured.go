@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	featuredRotationWindow = 15 * time.Minute // How long one set of featured profiles holds its slots
+	maxFeaturedCandidates  = 2000             // Subscribers loaded per tier when filling slots
+)
+
+// defaultFeaturedSlotPositions are the 1-based positions on a listing page
+// reserved for featured profiles when FEATURED_SLOT_POSITIONS isn't set
+var defaultFeaturedSlotPositions = []int{1, 5, 9, 13}
+
+// featuredSlotPositions reads FEATURED_SLOT_POSITIONS ("1,5,9,13") and keeps the
+// positions that fit on a page of limit profiles, leaving at least one organic result
+func featuredSlotPositions(limit int) []int {
+	positions := defaultFeaturedSlotPositions
+	if value := os.Getenv("FEATURED_SLOT_POSITIONS"); value != "" {
+		positions = nil
+		for _, part := range strings.Split(value, ",") {
+			if position, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && position > 0 {
+				positions = append(positions, position)
+			}
+		}
+	}
+
+	sorted := append([]int{}, positions...)
+	sort.Ints(sorted)
+
+	var fitting []int
+	for _, position := range sorted {
+		if position > limit || len(fitting) == limit-1 {
+			break
+		}
+		if len(fitting) == 0 || fitting[len(fitting)-1] != position {
+			fitting = append(fitting, position)
+		}
+	}
+	return fitting
+}
+
+// rankExpr is the rank weight of a running subscription, 0 without one. Subscribers
+// from before plans carried a weight count as the lowest paid tier.
+func rankExpr(now time.Time) bson.M {
+	return bson.M{"$cond": bson.A{
+		activeSubscriptionExpr(now),
+		bson.M{"$ifNull": bson.A{"$subscription_rank", 1}},
+		0,
+	}}
+}
+
+// featuredTier is a rank weight whose plans hold featured slots
+type featuredTier struct {
+	Rank  int
+	Slots int
+}
+
+// loadFeaturedTiers lists the tiers with featured slots, highest rank first
+func loadFeaturedTiers(ctx context.Context) ([]featuredTier, error) {
+	cursor, err := database.SubscriptionPlanCollection.Find(ctx, bson.M{"featured_slots": bson.M{"$gt": 0}})
+	if err != nil {
+		return nil, err
+	}
+	var plans []models.SubscriptionPlan
+	if err := cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+
+	// Plans sharing a weight form one tier, with the most slots any of them offers
+	slots := map[int]int{}
+	for _, plan := range plans {
+		if plan.RankWeight > 0 && plan.FeaturedSlots > slots[plan.RankWeight] {
+			slots[plan.RankWeight] = plan.FeaturedSlots
+		}
+	}
+
+	tiers := []featuredTier{}
+	for rank, count := range slots {
+		tiers = append(tiers, featuredTier{Rank: rank, Slots: count})
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Rank > tiers[j].Rank })
+	return tiers, nil
+}
+
+// featuredWindow is the featured rotation window at now
+func featuredWindow(now time.Time) int64 {
+	return now.Unix() / int64(featuredRotationWindow.Seconds())
+}
+
+// featuredSequence lists the profiles matching q that take the featured slots,
+// in the order a listing's pages show them. Each round gives every tier its
+// slots, top tier first. Within a tier the subscribers take turns, the order
+// shifting with every rotation window so every payer gets the same exposure
+// over time; the pages of one listing keep the window it started in.
+func featuredSequence(ctx context.Context, q ListingQuery, window int64, now time.Time) ([]primitive.ObjectID, error) {
+	tiers, err := loadFeaturedTiers(ctx)
+	if err != nil || len(tiers) == 0 {
+		return nil, err
+	}
+
+	members := make([][]primitive.ObjectID, len(tiers))
+	for i, tier := range tiers {
+		cursor, err := database.UserCollection.Aggregate(ctx, bson.A{
+			bson.M{"$match": q.Filter(now)},
+			bson.M{"$addFields": bson.M{"rank": rankExpr(now)}},
+			bson.M{"$match": bson.M{"rank": tier.Rank}},
+			bson.M{"$sort": bson.D{{Key: "_id", Value: 1}}},
+			bson.M{"$limit": maxFeaturedCandidates},
+			bson.M{"$project": bson.M{"_id": 1}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var docs []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, err
+		}
+		if len(docs) == 0 {
+			continue
+		}
+
+		start := window * int64(tier.Slots) % int64(len(docs))
+		for j := range docs {
+			members[i] = append(members[i], docs[(start+int64(j))%int64(len(docs))].ID)
+		}
+	}
+
+	var sequence []primitive.ObjectID
+	for added := true; added; {
+		added = false
+		for i, tier := range tiers {
+			n := min(tier.Slots, len(members[i]))
+			sequence = append(sequence, members[i][:n]...)
+			members[i] = members[i][n:]
+			added = added || n > 0
+		}
+	}
+	return sequence, nil
+}
+
+// featuredProfiles loads the listing fields of ids, keeping their order
+func featuredProfiles(ctx context.Context, ids []primitive.ObjectID, now time.Time) ([]bson.M, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	cursor, err := database.UserCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(listingProjection))
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	byID := map[primitive.ObjectID]bson.M{}
+	for _, doc := range docs {
+		id, _ := doc["_id"].(primitive.ObjectID)
+		doc["featured"] = true
+		byID[id] = doc
+	}
+	ordered := []bson.M{}
+	for _, id := range ids {
+		if doc, ok := byID[id]; ok {
+			ordered = append(ordered, doc)
+		}
+	}
+	return ordered, nil
+}
+
+// interleaveFeatured places featured profiles at positions among the organic
+// results, dropping organic duplicates of them
+func interleaveFeatured(organic []models.MinimalUserResponse, featured []bson.M, positions []int) []models.MinimalUserResponse {
+	if len(featured) == 0 {
+		return organic
+	}
+
+	slotted := map[primitive.ObjectID]bool{}
+	var slots []models.MinimalUserResponse
+	for _, doc := range featured {
+		user := minimalUserFromDoc(doc)
+		user.FeaturedSlot = true
+		slotted[user.ID] = true
+		slots = append(slots, user)
+	}
+
+	remaining := []models.MinimalUserResponse{}
+	for _, user := range organic {
+		if !slotted[user.ID] {
+			remaining = append(remaining, user)
+		}
+	}
+
+	page := make([]models.MinimalUserResponse, 0, len(remaining)+len(slots))
+	for i, user := range slots {
+		// Fill organic results up to the slot's position, or append when they run out
+		for len(page) < positions[i]-1 && len(remaining) > 0 {
+			page = append(page, remaining[0])
+			remaining = remaining[1:]
+		}
+		page = append(page, user)
+	}
+	return append(page, remaining...)
+}
+
+// SyncSubscriptionRanks copies the plan and rank weight of every running
+// subscription onto its profile, so subscribers from before plans carried a
+// weight, or whose plan was reweighted, rank correctly.
+func SyncSubscriptionRanks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	cursor, err := database.SubscriptionPlanCollection.Find(ctx, bson.M{})
+	if err != nil {
+		fmt.Printf("⚠️ Could not load plans for rank sync: %v\n", err)
+		return
+	}
+	var plans []models.SubscriptionPlan
+	if err := cursor.All(ctx, &plans); err != nil {
+		return
+	}
+
+	synced := int64(0)
+	for _, plan := range plans {
+		userIDs, err := database.SubscriptionCollection.Distinct(ctx, "user_id", bson.M{
+			"plan_id":     plan.ID,
+			"status":      "active",
+			"expiry_date": bson.M{"$gt": time.Now()},
+		})
+		if err != nil || len(userIDs) == 0 {
+			continue
+		}
+		result, err := database.UserCollection.UpdateMany(ctx,
+			bson.M{
+				"_id": bson.M{"$in": userIDs},
+				"$or": []bson.M{
+					{"subscription_plan_id": bson.M{"$ne": plan.ID}},
+					{"subscription_rank": bson.M{"$ne": plan.RankWeight}},
+				},
+			},
+			bson.M{"$set": bson.M{"subscription_plan_id": plan.ID, "subscription_rank": plan.RankWeight}},
+		)
+		if err != nil {
+			fmt.Printf("⚠️ Could not sync ranks for plan %s: %v\n", plan.Name, err)
+			continue
+		}
+		synced += result.ModifiedCount
+	}
+
+	if synced > 0 {
+		fmt.Printf("✅ Synced the listing rank of %d subscribers\n", synced)
+	}
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFeaturedSlotPositions(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   string
+		limit int
+		want  []int
+	}{
+		{"defaults", "", 20, []int{1, 5, 9, 13}},
+		{"defaults beyond the page", "", 6, []int{1, 5}},
+		{"page of one keeps it organic", "", 1, nil},
+		{"leaves one organic result", "1,2,3", 3, []int{1, 2}},
+		{"configured", "2,6", 20, []int{2, 6}},
+		{"sorted", "9, 1,5", 20, []int{1, 5, 9}},
+		{"duplicates dropped", "1,1,5", 20, []int{1, 5}},
+		{"invalid entries dropped", "0,-3,x,4", 20, []int{4}},
+		{"nothing valid", "x,y", 20, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FEATURED_SLOT_POSITIONS", tt.env)
+			if got := featuredSlotPositions(tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("featuredSlotPositions(%d) = %v, want %v", tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterleaveFeatured(t *testing.T) {
+	ids := make([]primitive.ObjectID, 8)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID()
+	}
+	organic := func(indexes ...int) []models.MinimalUserResponse {
+		users := []models.MinimalUserResponse{}
+		for _, i := range indexes {
+			users = append(users, models.MinimalUserResponse{ID: ids[i]})
+		}
+		return users
+	}
+	featured := func(indexes ...int) []bson.M {
+		var docs []bson.M
+		for _, i := range indexes {
+			docs = append(docs, bson.M{"_id": ids[i], "featured": true})
+		}
+		return docs
+	}
+
+	tests := []struct {
+		name      string
+		organic   []models.MinimalUserResponse
+		featured  []bson.M
+		positions []int
+		want      []int // Indexes into ids, featured ones negated minus one
+	}{
+		{"no featured", organic(0, 1, 2), nil, []int{1, 5}, []int{0, 1, 2}},
+		{"at their positions", organic(0, 1, 2, 3, 4), featured(6, 7), []int{1, 4}, []int{-7, 0, 1, -8, 2, 3, 4}},
+		{"fewer featured than positions", organic(0, 1, 2, 3), featured(6), []int{2, 4}, []int{0, -7, 1, 2, 3}},
+		{"appended when organic runs out", organic(0), featured(6, 7), []int{1, 5}, []int{-7, 0, -8}},
+		{"only featured", organic(), featured(6, 7), []int{1, 5}, []int{-7, -8}},
+		{"organic duplicates dropped", organic(0, 6, 1), featured(6), []int{1}, []int{-7, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interleaveFeatured(tt.organic, tt.featured, tt.positions)
+
+			var indexes []int
+			for _, user := range got {
+				for i, id := range ids {
+					if user.ID != id {
+						continue
+					}
+					if user.FeaturedSlot {
+						indexes = append(indexes, -i-1)
+					} else {
+						indexes = append(indexes, i)
+					}
+				}
+			}
+			if !reflect.DeepEqual(indexes, tt.want) {
+				t.Errorf("interleaveFeatured = %v, want %v", indexes, tt.want)
+			}
+		})
+	}
+}
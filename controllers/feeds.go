@@ -0,0 +1,521 @@
+package controllers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	feedItemLimit  = 50
+	sitemapMaxURLs = 50000 // Limit per sitemap file from the sitemaps.org protocol
+)
+
+// siteURL returns the public frontend URL that feed and sitemap links point to
+func siteURL() string {
+	if url := os.Getenv("SITE_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return "https://escorthub254.com"
+}
+
+// writeXML sends an XML document with the standard header
+func writeXML(c *gin.Context, contentType string, doc interface{}) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render XML")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
+
+// latestPublishedBlogs returns the newest published posts for feeds
+func latestPublishedBlogs(ctx context.Context, limit int64) ([]models.Blog, error) {
+	findOptions := options.Find().
+		SetSort(sortKeysDoc(blogListSort)).
+		SetLimit(limit)
+
+	cursor, err := blogCollection.Find(ctx, bson.M{"published": true}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var blogs []models.Blog
+	if err = cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// blogPublishDate falls back to the creation date for posts without published_at
+func blogPublishDate(blog models.Blog) time.Time {
+	if !blog.PublishedAt.IsZero() {
+		return blog.PublishedAt
+	}
+	return blog.CreatedAt
+}
+
+// ==================== RSS ====================
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description string        `xml:"description"`
+	Category    string        `xml:"category,omitempty"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// GetBlogRSS - GET /feeds/blog.rss
+func GetBlogRSS(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	blogs, err := latestPublishedBlogs(ctx, feedItemLimit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to fetch blogs")
+		return
+	}
+
+	site := siteURL()
+	lastBuild := time.Now()
+	if len(blogs) > 0 {
+		lastBuild = blogs[0].UpdatedAt
+	}
+
+	channel := rssChannel{
+		Title:         "Escorthub254 Blog",
+		Link:          site + "/blog",
+		Description:   "Tips, guides, and insights about escort services in Nairobi",
+		Language:      "en-ke",
+		LastBuildDate: lastBuild.UTC().Format(time.RFC1123Z),
+		AtomLink: atomLink{
+			Href: site + "/feeds/blog.rss",
+			Rel:  "self",
+			Type: "application/rss+xml",
+		},
+	}
+
+	for _, blog := range blogs {
+		link := site + "/blog/" + blog.Slug
+		item := rssItem{
+			Title:       blog.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			Description: blog.Description,
+			Category:    blog.Category,
+			PubDate:     blogPublishDate(blog).UTC().Format(time.RFC1123Z),
+		}
+		if blog.Image != "" {
+			// Length is unknown for remote images; 0 is the accepted placeholder
+			item.Enclosure = &rssEnclosure{URL: blog.Image, Type: "image/jpeg"}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	writeXML(c, "application/rss+xml; charset=utf-8", rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	})
+}
+
+// ==================== ATOM ====================
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string        `xml:"title"`
+	ID        string        `xml:"id"`
+	Link      atomLink      `xml:"link"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Summary   string        `xml:"summary"`
+	Author    atomAuthor    `xml:"author"`
+	Category  *atomCategory `xml:"category,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// GetBlogAtom - GET /feeds/blog.atom
+func GetBlogAtom(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	blogs, err := latestPublishedBlogs(ctx, feedItemLimit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to fetch blogs")
+		return
+	}
+
+	site := siteURL()
+	updated := time.Now()
+	for i, blog := range blogs {
+		if i == 0 || blog.UpdatedAt.After(updated) {
+			updated = blog.UpdatedAt
+		}
+	}
+
+	feed := atomFeed{
+		Title:   "Escorthub254 Blog",
+		ID:      site + "/blog",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: site + "/feeds/blog.atom", Rel: "self", Type: "application/atom+xml"},
+			{Href: site + "/blog", Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	for _, blog := range blogs {
+		link := site + "/blog/" + blog.Slug
+		entry := atomEntry{
+			Title:     blog.Title,
+			ID:        link,
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Published: blogPublishDate(blog).UTC().Format(time.RFC3339),
+			Updated:   blog.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   blog.Description,
+			Author:    atomAuthor{Name: blog.Author},
+		}
+		if blog.Category != "" {
+			entry.Category = &atomCategory{Term: blog.Category}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	writeXML(c, "application/atom+xml; charset=utf-8", feed)
+}
+
+// ==================== SITEMAP ====================
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// sitemapSection is one group of URLs that can be split across sitemap files
+type sitemapSection struct {
+	Name  string
+	Count func(ctx context.Context) (int64, error)
+	URLs  func(ctx context.Context, skip, limit int64) ([]sitemapURL, error)
+}
+
+func sitemapDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+var sitemapSections = []sitemapSection{
+	{
+		Name:  "pages",
+		Count: func(ctx context.Context) (int64, error) { return int64(len(staticSitemapPaths)), nil },
+		URLs: func(ctx context.Context, skip, limit int64) ([]sitemapURL, error) {
+			var urls []sitemapURL
+			for i := skip; i < int64(len(staticSitemapPaths)) && i < skip+limit; i++ {
+				urls = append(urls, sitemapURL{Loc: siteURL() + staticSitemapPaths[i]})
+			}
+			return urls, nil
+		},
+	},
+	{
+		Name: "blog",
+		Count: func(ctx context.Context) (int64, error) {
+			return blogCollection.CountDocuments(ctx, bson.M{"published": true})
+		},
+		URLs: blogSitemapURLs,
+	},
+	{
+		Name: "locations",
+		Count: func(ctx context.Context) (int64, error) {
+			locations, err := sitemapLocations(ctx)
+			return int64(len(locations)), err
+		},
+		URLs: func(ctx context.Context, skip, limit int64) ([]sitemapURL, error) {
+			locations, err := sitemapLocations(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var urls []sitemapURL
+			for i := skip; i < int64(len(locations)) && i < skip+limit; i++ {
+				urls = append(urls, locations[i])
+			}
+			return urls, nil
+		},
+	},
+	{
+		Name: "profiles",
+		Count: func(ctx context.Context) (int64, error) {
+			return database.UserCollection.CountDocuments(ctx, visibleProfileFilter(time.Now()))
+		},
+		URLs: profileSitemapURLs,
+	},
+}
+
+// staticSitemapPaths are frontend pages that always exist
+var staticSitemapPaths = []string{"/", "/providers", "/blog", "/about"}
+
+func blogSitemapURLs(ctx context.Context, skip, limit int64) ([]sitemapURL, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit).
+		SetProjection(bson.M{"slug": 1, "updated_at": 1})
+
+	cursor, err := blogCollection.Find(ctx, bson.M{"published": true}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var urls []sitemapURL
+	for cursor.Next(ctx) {
+		var blog models.Blog
+		if err := cursor.Decode(&blog); err != nil {
+			continue
+		}
+		urls = append(urls, sitemapURL{
+			Loc:     siteURL() + "/blog/" + blog.Slug,
+			LastMod: sitemapDate(blog.UpdatedAt),
+		})
+	}
+	return urls, cursor.Err()
+}
+
+func profileSitemapURLs(ctx context.Context, skip, limit int64) ([]sitemapURL, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit).
+		SetProjection(bson.M{"_id": 1, "updated_at": 1})
+
+	cursor, err := database.UserCollection.Find(ctx, visibleProfileFilter(time.Now()), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var urls []sitemapURL
+	for cursor.Next(ctx) {
+		var profile struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			UpdatedAt time.Time          `bson:"updated_at"`
+		}
+		if err := cursor.Decode(&profile); err != nil {
+			continue
+		}
+		urls = append(urls, sitemapURL{
+			Loc:     siteURL() + "/provider/" + profile.ID.Hex(),
+			LastMod: sitemapDate(profile.UpdatedAt),
+		})
+	}
+	return urls, cursor.Err()
+}
+
+// sitemapLocations lists the provider listing of every location with visible profiles.
+// The site has no location landing pages, so entries point at /providers filtered by location.
+func sitemapLocations(ctx context.Context) ([]sitemapURL, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleProfileFilter(time.Now())}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$location"}}},
+			"updated_at": bson.M{"$max": "$updated_at"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := database.UserCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Location  string    `bson:"_id"`
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	// Different spellings can share a slug; keep the first one
+	seen := map[string]bool{}
+	var urls []sitemapURL
+	for _, result := range results {
+		slug := models.Slugify(result.Location)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		urls = append(urls, sitemapURL{
+			Loc:     siteURL() + "/providers?location=" + url.QueryEscape(slug),
+			LastMod: sitemapDate(result.UpdatedAt),
+		})
+	}
+	return urls, nil
+}
+
+// GetSitemap - GET /sitemap.xml
+// Serves a single urlset when everything fits, otherwise a sitemap index.
+func GetSitemap(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	counts := make([]int64, len(sitemapSections))
+	var total int64
+	for i, section := range sitemapSections {
+		count, err := section.Count(ctx)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Failed to build sitemap")
+			return
+		}
+		counts[i] = count
+		total += count
+	}
+
+	if total <= sitemapMaxURLs {
+		urlSet := sitemapURLSet{}
+		for _, section := range sitemapSections {
+			urls, err := section.URLs(ctx, 0, sitemapMaxURLs)
+			if err != nil {
+				c.String(http.StatusInternalServerError, "Failed to build sitemap")
+				return
+			}
+			urlSet.URLs = append(urlSet.URLs, urls...)
+		}
+		writeXML(c, "application/xml; charset=utf-8", urlSet)
+		return
+	}
+
+	index := sitemapIndex{}
+	for i, section := range sitemapSections {
+		files := (counts[i] + sitemapMaxURLs - 1) / sitemapMaxURLs
+		for file := int64(1); file <= files; file++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+				Loc: fmt.Sprintf("%s/sitemaps/%s-%d.xml", siteURL(), section.Name, file),
+			})
+		}
+	}
+	writeXML(c, "application/xml; charset=utf-8", index)
+}
+
+// GetSitemapFile - GET /sitemaps/:file (e.g. profiles-2.xml)
+func GetSitemapFile(c *gin.Context) {
+	name := strings.TrimSuffix(c.Param("file"), ".xml")
+	dash := strings.LastIndex(name, "-")
+	if dash < 0 {
+		c.String(http.StatusNotFound, "Sitemap not found")
+		return
+	}
+
+	file, err := strconv.ParseInt(name[dash+1:], 10, 64)
+	if err != nil || file < 1 {
+		c.String(http.StatusNotFound, "Sitemap not found")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, section := range sitemapSections {
+		if section.Name != name[:dash] {
+			continue
+		}
+
+		urls, err := section.URLs(ctx, (file-1)*sitemapMaxURLs, sitemapMaxURLs)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Failed to build sitemap")
+			return
+		}
+		if len(urls) == 0 {
+			c.String(http.StatusNotFound, "Sitemap not found")
+			return
+		}
+		writeXML(c, "application/xml; charset=utf-8", sitemapURLSet{URLs: urls})
+		return
+	}
+
+	c.String(http.StatusNotFound, "Sitemap not found")
+}
@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	geoFuzzMeters         = 750   // Device coordinates are moved up to this far before storing
+	geoSnapDegrees        = 0.005 // Then snapped to a grid of about 550 m
+	defaultNearbyRadiusKm = 10
+	maxNearbyRadiusKm     = 100
+	nearbyDistanceStep    = 500 // Distances are rounded up to this many metres before sorting
+)
+
+// coarsenDevicePoint is what is stored of a device position: never the exact spot
+func coarsenDevicePoint(point models.GeoPoint) *models.GeoPoint {
+	coarse := point.Fuzz(geoFuzzMeters).Snap(geoSnapDegrees)
+	return &coarse
+}
+
+// SetGeoLocation - PUT /auth/geo-location {"area": "westlands"} or {"lat", "lng"}
+// area is any location of the taxonomy. Device coordinates are always fuzzed and
+// snapped to a grid, so the stored point never is the provider's exact position.
+func SetGeoLocation(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var request struct {
+		Area string   `json:"area"`
+		Lat  *float64 `json:"lat"`
+		Lng  *float64 `json:"lng"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var point *models.GeoPoint
+	var source string
+	switch {
+	case strings.TrimSpace(request.Area) != "":
+		location, err := ResolveLocation(ctx, request.Area)
+		if err != nil || location.Center == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown area: " + request.Area})
+			return
+		}
+		point = location.Center
+		source = models.GeoSourceArea
+	case request.Lat != nil && request.Lng != nil:
+		if point, err = models.NewGeoPoint(*request.Lat, *request.Lng); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		point = coarsenDevicePoint(*point)
+		source = models.GeoSourceDevice
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide an area or lat and lng"})
+		return
+	}
+
+	result, err := database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$set": bson.M{"geo_location": point, "geo_source": source, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save location"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Location saved",
+		"source":  source,
+	})
+}
+
+// ClearGeoLocation - DELETE /auth/geo-location
+// Removes the profile from nearby searches.
+func ClearGeoLocation(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$unset": bson.M{"geo_location": "", "geo_source": ""}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear location"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Location cleared",
+	})
+}
+
+// SearchNearby - GET /search/nearby?lat=&lng=&radius=10&cursor= plus the /search filters
+// Results are ordered by distance; radius is in whole kilometres. Distances are
+// rounded before they are sorted on, so neither the order nor the cursor gives
+// away how far exactly anyone is.
+func SearchNearby(c *gin.Context) {
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	if latErr != nil || lngErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "lat and lng are required"})
+		return
+	}
+	center, err := models.NewGeoPoint(lat, lng)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	radius := float64(defaultNearbyRadiusKm)
+	if value := c.Query("radius"); value != "" {
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "radius must be a positive number of kilometres"})
+			return
+		}
+		radius = min(math.Ceil(radius), maxNearbyRadiusKm)
+	}
+
+	query, err := parseListingQuery(c, 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := query.resolve(ctx); err != nil {
+		listingError(c, err)
+		return
+	}
+
+	now := time.Now()
+	page, err := runListingPipeline(ctx, query, bson.A{
+		bson.M{"$geoNear": bson.M{
+			"near":          center,
+			"key":           "geo_location",
+			"distanceField": "distance",
+			"maxDistance":   radius * 1000,
+			"spherical":     true,
+			"query":         query.GeoFilter(now),
+		}},
+		bson.M{"$addFields": bson.M{
+			"featured": activeSubscriptionExpr(now),
+			"distance": bson.M{"$multiply": bson.A{
+				bson.M{"$ceil": bson.M{"$divide": bson.A{"$distance", nearbyDistanceStep}}},
+				nearbyDistanceStep,
+			}},
+		}},
+	}, nearbySort)
+	if err != nil {
+		listingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        page.Users,
+		"count":       len(page.Users),
+		"pagination":  listingPagination(query, page),
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+		"radius_km":   radius,
+		"message":     "Nearby profiles retrieved successfully",
+	})
+}
+
+// MigrateDeviceLocations snaps device positions stored before they were always
+// coarsened, including those saved exactly with the former fuzz=false option
+func MigrateDeviceLocations() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cursor, err := database.UserCollection.Find(ctx,
+		bson.M{"geo_source": models.GeoSourceDevice, "geo_location": bson.M{"$ne": nil}},
+		options.Find().SetProjection(bson.M{"geo_location": 1}),
+	)
+	if err != nil {
+		fmt.Printf("⚠️ Could not load users for device location migration: %v\n", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var user struct {
+			ID          primitive.ObjectID `bson:"_id"`
+			GeoLocation *models.GeoPoint   `bson:"geo_location"`
+		}
+		if err := cursor.Decode(&user); err != nil || user.GeoLocation == nil || len(user.GeoLocation.Coordinates) != 2 {
+			continue
+		}
+
+		// Snapping is idempotent, so points on the grid already are left alone
+		snapped := user.GeoLocation.Snap(geoSnapDegrees)
+		if snapped.Lat() == user.GeoLocation.Lat() && snapped.Lng() == user.GeoLocation.Lng() {
+			continue
+		}
+		if _, err := database.UserCollection.UpdateOne(ctx,
+			bson.M{"_id": user.ID},
+			bson.M{"$set": bson.M{"geo_location": coarsenDevicePoint(*user.GeoLocation)}},
+		); err != nil {
+			fmt.Printf("⚠️ Could not coarsen location of user %s: %v\n", user.ID.Hex(), err)
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		fmt.Printf("✅ Coarsened the device locations of %d profiles\n", migrated)
+	}
+}
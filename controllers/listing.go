@@ -0,0 +1,571 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Listing sort modes
+const (
+	SortNewest         = "newest"
+	SortFeatured       = "featured"
+	SortRecentlyActive = "recently_active"
+	SortRelevance      = "relevance" // Default when searching by text
+)
+
+const maxListingLimit = 100
+
+// ErrInvalidCursor is returned when a listing cursor can't be decoded for the requested sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// listingSorts orders public listings. Every order ends in _id so pages are stable.
+// "rank" is computed per query: the rank weight of a running subscription's plan, 0 without one.
+// "active_at" is the last activity, or the registration date for profiles never seen since.
+// "relevance" is the text score, boosted for featured profiles.
+// Every order starts with "boost", so paid bumps lead whichever listing they match.
+var listingSorts = map[string][]sortKey{
+	SortNewest:         {{Field: "boost", Desc: true}, {Field: "created_at", Desc: true}, {Field: "_id", Desc: true}},
+	SortFeatured:       {{Field: "boost", Desc: true}, {Field: "rank", Desc: true}, {Field: "created_at", Desc: true}, {Field: "_id", Desc: true}},
+	SortRecentlyActive: {{Field: "boost", Desc: true}, {Field: "active_at", Desc: true}, {Field: "created_at", Desc: true}, {Field: "_id", Desc: true}},
+	SortRelevance:      {{Field: "boost", Desc: true}, {Field: "relevance", Desc: true}, {Field: "created_at", Desc: true}, {Field: "_id", Desc: true}},
+}
+
+// nearbySort orders nearby results, closest first
+var nearbySort = []sortKey{{Field: "distance"}, {Field: "_id"}}
+
+// listingProjection holds the fields needed to build a MinimalUserResponse
+var listingProjection = bson.M{
+	"first_name":          1,
+	"last_name":           1,
+	"phone_no":            1,
+	"image_url":           1,
+	"location":            1,
+	"services":            1,
+	"has_subscription":    1,
+	"subscription_expiry": 1,
+	"verified_at":         1,
+	"created_at":          1,
+	"last_active_at":      1,
+	"active_at":           1,
+	"featured":            1,
+	"rank":                1,
+	"boost":               1,
+	"relevance":           1,
+	"distance":            1,
+}
+
+// ListingQuery selects and orders public profiles. All filters combine with AND.
+type ListingQuery struct {
+	Text        string   // q: matched against name, location and services
+	Location    string   // Substring of the location
+	Services    []string // Profiles must offer all of them; mapped onto catalogue names by resolve
+	Gender      string
+	Nationality string
+	Orientation string
+	MinAge      int
+	MaxAge      int
+	Verified    bool // Only profiles with the verified badge
+	Sort        string
+	Page        int // Offset paging, ignored when Cursor is set
+	Limit       int
+	Cursor      string // next_cursor of the previous page
+
+	// Set by resolve when Location names a place of the taxonomy
+	locationID *primitive.ObjectID
+	// Set by resolve: the words of Text, expanded for prefix and fuzzy matching
+	text textSearch
+	// Set by applyCursor: the sort values of the profile the page starts after
+	after bson.A
+	// Set by applyCursor or RunListing: the featured rotation window the listing
+	// started in and the featured profiles its earlier pages showed
+	window        int64
+	featuredShown int
+	// Set by RunListing: featured profiles taking the place of organic results on the page
+	featuredOnPage int
+}
+
+// parseListingQuery reads listing filters from the query string. Services may be
+// repeated (?service=a&service=b) or comma separated.
+func parseListingQuery(c *gin.Context, defaultLimit int) (ListingQuery, error) {
+	return parseListingValues(c.Request.URL.Query(), defaultLimit)
+}
+
+// parseListingValues reads listing filters from query parameters, such as those
+// of a request or a saved search
+func parseListingValues(values url.Values, defaultLimit int) (ListingQuery, error) {
+	query := ListingQuery{
+		Text:        strings.TrimSpace(values.Get("q")),
+		Location:    strings.TrimSpace(values.Get("location")),
+		Gender:      strings.TrimSpace(values.Get("gender")),
+		Nationality: strings.TrimSpace(values.Get("nationality")),
+		Orientation: strings.TrimSpace(values.Get("orientation")),
+		Verified:    values.Get("verified") == "true",
+		Sort:        values.Get("sort"),
+		Cursor:      values.Get("cursor"),
+	}
+
+	for _, value := range values["service"] {
+		for _, service := range strings.Split(value, ",") {
+			if service = strings.TrimSpace(service); service != "" {
+				query.Services = append(query.Services, service)
+			}
+		}
+	}
+
+	if query.Sort == "" {
+		query.Sort = SortNewest
+		if query.Text != "" {
+			query.Sort = SortRelevance
+		}
+	}
+	if _, ok := listingSorts[query.Sort]; !ok {
+		return query, fmt.Errorf("sort must be %s, %s, %s or %s", SortNewest, SortFeatured, SortRecentlyActive, SortRelevance)
+	}
+	if query.Sort == SortRelevance && query.Text == "" {
+		return query, fmt.Errorf("sort=%s needs a search query (q)", SortRelevance)
+	}
+
+	var err error
+	if query.MinAge, err = optionalInt(values, "min_age"); err != nil {
+		return query, err
+	}
+	if query.MaxAge, err = optionalInt(values, "max_age"); err != nil {
+		return query, err
+	}
+	if query.MinAge != 0 && query.MinAge < models.MinimumAge {
+		query.MinAge = models.MinimumAge
+	}
+	if query.MaxAge != 0 && query.MaxAge < query.MinAge {
+		return query, fmt.Errorf("max_age must not be below min_age")
+	}
+
+	query.Page = 1
+	if value := values.Get("page"); value != "" {
+		if query.Page, err = strconv.Atoi(value); err != nil || query.Page <= 0 {
+			return query, fmt.Errorf("page must be a positive number")
+		}
+	}
+	query.Limit = limitValue(values.Get("limit"), defaultLimit, maxListingLimit)
+
+	return query, nil
+}
+
+// optionalInt reads a non-negative integer query parameter, 0 when absent
+func optionalInt(values url.Values, name string) (int, error) {
+	value := values.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a positive number", name)
+	}
+	return n, nil
+}
+
+// HasCriteria reports whether any filter is set
+func (q ListingQuery) HasCriteria() bool {
+	return q.Text != "" || q.Location != "" || len(q.Services) > 0 || q.Gender != "" ||
+		q.Nationality != "" || q.Orientation != "" || q.MinAge > 0 || q.MaxAge > 0 || q.Verified
+}
+
+// Filter combines the public visibility policy with the query's filters. Text is
+// matched through the profile_text index, so the filter must open the pipeline.
+func (q ListingQuery) Filter(now time.Time) bson.M {
+	return q.filter(now, true)
+}
+
+// GeoFilter is Filter for the query of a $geoNear stage, where $text isn't allowed
+func (q ListingQuery) GeoFilter(now time.Time) bson.M {
+	return q.filter(now, false)
+}
+
+func (q ListingQuery) filter(now time.Time, textIndex bool) bson.M {
+	filter := visibleProfileFilter(now)
+
+	var conditions []bson.M
+	if len(q.text) > 0 {
+		// $text matches profiles with any of the words and scores them; the
+		// patterns require every term, so both paths return the same profiles
+		if textIndex {
+			filter["$text"] = q.text.indexFilter()
+		}
+		conditions = append(conditions, q.text.patternFilter()...)
+	}
+	if q.locationID != nil {
+		// A county or town includes every place inside it
+		conditions = append(conditions, bson.M{"location_path": *q.locationID})
+	} else if q.Location != "" {
+		conditions = append(conditions, bson.M{"location": bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(q.Location), Options: "i"}}})
+	}
+	if len(q.Services) > 0 {
+		conditions = append(conditions, bson.M{"services": bson.M{"$all": q.Services}})
+	}
+	if q.Gender != "" {
+		conditions = append(conditions, bson.M{"gender": exactMatch(q.Gender)})
+	}
+	if q.Nationality != "" {
+		conditions = append(conditions, bson.M{"nationality": exactMatch(q.Nationality)})
+	}
+	if q.Orientation != "" {
+		conditions = append(conditions, bson.M{"sexual_orientation": exactMatch(q.Orientation)})
+	}
+
+	// Ages are filtered on the date of birth so they never go stale
+	if q.MinAge > 0 {
+		conditions = append(conditions, bson.M{"date_of_birth": bson.M{"$lte": now.AddDate(-q.MinAge, 0, 0)}})
+	}
+	if q.MaxAge > 0 {
+		conditions = append(conditions, bson.M{"date_of_birth": bson.M{"$gt": now.AddDate(-(q.MaxAge + 1), 0, 0)}})
+	}
+
+	if q.Verified {
+		conditions = append(conditions, bson.M{"verified_at": bson.M{"$ne": nil}})
+	}
+
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	return filter
+}
+
+// resolve parses the search text and maps the query onto the location taxonomy and
+// the services catalogue. A location that isn't a known place is still matched
+// against the location labels.
+func (q *ListingQuery) resolve(ctx context.Context) error {
+	if q.Text != "" && q.text == nil {
+		text, err := parseTextSearch(ctx, q.Text)
+		if err != nil {
+			return err
+		}
+		q.text = text
+	}
+	// Text made only of punctuation matches everything; there is no score to sort on
+	if len(q.text) == 0 && q.Sort == SortRelevance {
+		q.Sort = SortFeatured
+	}
+
+	if len(q.Services) > 0 {
+		catalogue, err := loadServiceCatalogue(ctx, false)
+		if err != nil {
+			return err
+		}
+		for i, value := range q.Services {
+			if service, ok := catalogue.lookup(value); ok {
+				q.Services[i] = service.Name
+			}
+		}
+	}
+
+	if q.Location == "" || q.locationID != nil {
+		return nil
+	}
+	location, err := ResolveLocation(ctx, q.Location)
+	if err == ErrUnknownLocation {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	q.locationID = &location.ID
+	return nil
+}
+
+// ListingPage is one page of a public listing
+type ListingPage struct {
+	Users      []models.MinimalUserResponse
+	Page       int    // Page number, also when reached through a cursor
+	Total      int64  // Profiles matching the query, across all pages
+	HasMore    bool   // Another page follows this one
+	NextCursor string // Fetches the following page; empty on the last page
+
+	last bson.A // Sort values of the last organic profile, or those the page started after
+}
+
+// TotalPages is the number of pages at the query's page size
+func (p ListingPage) TotalPages(limit int) int {
+	return int((p.Total + int64(limit) - 1) / int64(limit))
+}
+
+// applyCursor decodes the cursor into the sort values the page starts after, the
+// number of the page it leads to and the state of the listing's featured slots
+func (q *ListingQuery) applyCursor(keys []sortKey) error {
+	if q.Cursor == "" || q.after != nil {
+		return nil
+	}
+	values, err := decodeCursor(q.Cursor, len(keys)+3)
+	if err != nil {
+		// Cursors from before featured slots were tracked carry only the page
+		if values, err = decodeCursor(q.Cursor, len(keys)+1); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+
+	state := make([]int64, 0, 3)
+	for _, value := range values[len(keys):] {
+		switch n := value.(type) {
+		case int32:
+			state = append(state, int64(n))
+		case int64:
+			state = append(state, n)
+		default:
+			return ErrInvalidCursor
+		}
+	}
+	if state[0] <= 0 {
+		return ErrInvalidCursor
+	}
+	q.Page = int(state[0])
+	if len(state) == 3 {
+		if state[2] < 0 {
+			return ErrInvalidCursor
+		}
+		q.window = state[1]
+		q.featuredShown = int(state[2])
+	}
+	q.after = values[:len(keys)]
+	return nil
+}
+
+// nextCursor is the cursor of the page following the one ending at last
+func (q ListingQuery) nextCursor(last bson.A) string {
+	values := append(bson.A{}, last...)
+	return encodeCursor(append(values, q.Page+1, q.window, q.featuredShown+q.featuredOnPage))
+}
+
+// exhaustedCursorValues are sort values no profile sorts after, for cursors of
+// pages that only have featured profiles left to show
+func exhaustedCursorValues(keys []sortKey) bson.A {
+	values := bson.A{}
+	for _, key := range keys {
+		if key.Desc {
+			values = append(values, primitive.MinKey{})
+		} else {
+			values = append(values, primitive.MaxKey{})
+		}
+	}
+	return values
+}
+
+// RunListing fetches a page of public profiles. Profiles of plans with featured
+// slots are placed at the featured positions of the page, in turns.
+func RunListing(ctx context.Context, q ListingQuery) (ListingPage, error) {
+	if err := q.resolve(ctx); err != nil {
+		return ListingPage{}, err
+	}
+	keys := listingSorts[q.Sort]
+	if err := q.applyCursor(keys); err != nil {
+		return ListingPage{}, err
+	}
+
+	now := time.Now()
+	if q.window == 0 {
+		q.window = featuredWindow(now)
+	}
+
+	// Featured profiles are shown only in their slots, each once per listing: the
+	// organic results leave out everyone in the sequence
+	positions := featuredSlotPositions(q.Limit)
+	var sequence []primitive.ObjectID
+	if len(positions) > 0 {
+		var err error
+		if sequence, err = featuredSequence(ctx, q, q.window, now); err != nil {
+			return ListingPage{}, err
+		}
+	}
+	if q.after == nil {
+		q.featuredShown = (q.Page - 1) * len(positions)
+	}
+	q.featuredShown = min(q.featuredShown, len(sequence))
+	featured, err := featuredProfiles(ctx, sequence[q.featuredShown:min(q.featuredShown+len(positions), len(sequence))], now)
+	if err != nil {
+		return ListingPage{}, err
+	}
+
+	stages := bson.A{bson.M{"$match": q.Filter(now)}}
+	if len(sequence) > 0 {
+		stages = append(stages, bson.M{"$match": bson.M{"_id": bson.M{"$nin": sequence}}})
+	}
+	stages = append(stages, bson.M{"$addFields": bson.M{
+		"featured":  activeSubscriptionExpr(now),
+		"rank":      rankExpr(now),
+		"boost":     boostExpr(now, q.locationID),
+		"active_at": bson.M{"$ifNull": bson.A{"$last_active_at", "$created_at"}},
+	}})
+	if q.Sort == SortRelevance {
+		stages = append(stages, bson.M{"$addFields": bson.M{"relevance": relevanceExpr()}})
+	}
+
+	// Featured slots take the place of organic results so pages keep their size
+	q.featuredOnPage = len(featured)
+
+	page, err := runListingPipeline(ctx, q, stages, keys)
+	if err != nil {
+		return ListingPage{}, err
+	}
+	page.Total += int64(len(sequence))
+	if !page.HasMore && q.featuredShown+q.featuredOnPage < len(sequence) {
+		last := page.last
+		if last == nil {
+			last = exhaustedCursorValues(keys)
+		}
+		page.HasMore = true
+		page.NextCursor = q.nextCursor(last)
+	}
+	page.Users = interleaveFeatured(page.Users, featured, positions)
+	return page, nil
+}
+
+// runListingPipeline sorts the profiles produced by the given stages by keys and
+// returns one page of them. With a cursor the page starts right after the profile
+// the cursor was taken from, so profiles registering meanwhile don't shift pages.
+func runListingPipeline(ctx context.Context, q ListingQuery, stages bson.A, keys []sortKey) (ListingPage, error) {
+	if err := q.applyCursor(keys); err != nil {
+		return ListingPage{}, err
+	}
+
+	// Earlier pages showed Limit profiles each, some of them featured
+	limit := q.Limit - q.featuredOnPage
+	items := bson.A{}
+	if q.after != nil {
+		items = append(items, bson.M{"$match": afterCursorFilter(keys, q.after)})
+	} else {
+		items = append(items, bson.M{"$skip": (q.Page-1)*q.Limit - q.featuredShown})
+	}
+	// Fetch one extra profile to know whether another page exists
+	items = append(items,
+		bson.M{"$limit": limit + 1},
+		bson.M{"$project": listingProjection},
+	)
+
+	pipeline := append(stages,
+		bson.M{"$sort": sortKeysDoc(keys)},
+		bson.M{"$facet": bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"items": items,
+		}},
+	)
+
+	cursor, err := database.UserCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return ListingPage{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Items []bson.M `bson:"items"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return ListingPage{}, err
+	}
+
+	page := ListingPage{Users: []models.MinimalUserResponse{}, Page: q.Page, last: q.after}
+	if len(results) == 0 {
+		return page, nil
+	}
+	if len(results[0].Total) > 0 {
+		page.Total = results[0].Total[0].Count
+	}
+
+	docs := results[0].Items
+	if len(docs) > limit {
+		docs = docs[:limit]
+		page.HasMore = true
+	}
+	if len(docs) > 0 {
+		last := docs[len(docs)-1]
+		page.last = bson.A{}
+		for _, key := range keys {
+			page.last = append(page.last, last[key.Field])
+		}
+	}
+	if page.HasMore {
+		page.NextCursor = q.nextCursor(page.last)
+	}
+	for _, doc := range docs {
+		page.Users = append(page.Users, minimalUserFromDoc(doc))
+	}
+	return page, nil
+}
+
+// activeSubscriptionExpr is true for profiles whose subscription is running at now
+func activeSubscriptionExpr(now time.Time) bson.M {
+	return bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$has_subscription", true}},
+		bson.M{"$gt": bson.A{"$subscription_expiry", now}},
+	}}
+}
+
+// minimalUserFromDoc builds the public card of a listed profile
+func minimalUserFromDoc(doc bson.M) models.MinimalUserResponse {
+	id, _ := doc["_id"].(primitive.ObjectID)
+	firstName, _ := doc["first_name"].(string)
+	lastName, _ := doc["last_name"].(string)
+	phoneNo, _ := doc["phone_no"].(string)
+	imageUrl, _ := doc["image_url"].(string)
+	location, _ := doc["location"].(string)
+	featured, _ := doc["featured"].(bool)
+
+	var distance string
+	if meters, ok := doc["distance"].(float64); ok {
+		distance = models.DistanceLabel(meters)
+	}
+
+	return models.MinimalUserResponse{
+		ID:              id,
+		FullName:        firstName + " " + lastName,
+		PhoneNo:         phoneNo,
+		ImageUrl:        imageUrl,
+		Services:        servicesList(doc["services"]),
+		Location:        location,
+		HasSubscription: featured,
+		Verified:        doc["verified_at"] != nil,
+		Distance:        distance,
+	}
+}
+
+// servicesList reads services stored either as an array or, for old profiles,
+// as a comma separated string
+func servicesList(value interface{}) []string {
+	var services []string
+	switch s := value.(type) {
+	case primitive.A:
+		for _, v := range s {
+			if str, ok := v.(string); ok {
+				services = append(services, str)
+			}
+		}
+	case string:
+		if s != "" {
+			services = strings.Split(s, ",")
+			for i, service := range services {
+				services[i] = strings.TrimSpace(service)
+			}
+		}
+	case []interface{}:
+		for _, v := range s {
+			if str, ok := v.(string); ok {
+				services = append(services, str)
+			}
+		}
+	case []string:
+		services = s
+	}
+	return services
+}
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrUnknownLocation is returned when a location can't be found in the taxonomy
+var ErrUnknownLocation = errors.New("unknown location")
+
+// ResolveLocation finds a location by ID, slug, name or alias. When a name is
+// shared between levels (Nakuru county and Nakuru town) the broader place wins.
+func ResolveLocation(ctx context.Context, value string) (*models.Location, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, ErrUnknownLocation
+	}
+
+	filter := bson.M{"keys": strings.ToLower(value), "is_active": true}
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		filter = bson.M{"_id": id, "is_active": true}
+	}
+
+	var location models.Location
+	err := database.LocationCollection.FindOne(ctx, filter,
+		options.FindOne().SetSort(bson.D{{Key: "level", Value: 1}}),
+	).Decode(&location)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUnknownLocation
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// resolveFreeTextLocation maps legacy free-text locations such as "Westlands, Nairobi"
+// or "nbi - kilimani" onto the taxonomy, trying the whole text and then each part
+func resolveFreeTextLocation(ctx context.Context, text string) (*models.Location, error) {
+	location, err := ResolveLocation(ctx, text)
+	if err != ErrUnknownLocation {
+		return location, err
+	}
+
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '-' || r == '/' || r == '(' || r == ')'
+	})
+	for _, part := range parts {
+		part = strings.TrimSpace(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(part)), " county"))
+		if part == "" {
+			continue
+		}
+		location, err := ResolveLocation(ctx, part)
+		if err != ErrUnknownLocation {
+			return location, err
+		}
+	}
+	return nil, ErrUnknownLocation
+}
+
+// locationFields is the profile update that places a user at location
+func locationFields(location *models.Location) bson.M {
+	return bson.M{
+		"location":      location.Label,
+		"location_id":   location.ID,
+		"location_path": location.Path,
+	}
+}
+
+// PlaceUserAt stores location on the profile. Unless the provider shared device
+// coordinates, the nearby search uses the centre of the location.
+func PlaceUserAt(ctx context.Context, userObjID primitive.ObjectID, location *models.Location) error {
+	fields := locationFields(location)
+	fields["updated_at"] = time.Now()
+	if _, err := database.UserCollection.UpdateOne(ctx, bson.M{"_id": userObjID}, bson.M{"$set": fields}); err != nil {
+		return err
+	}
+
+	if location.Center == nil {
+		return nil
+	}
+	_, err := database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID, "geo_source": bson.M{"$ne": models.GeoSourceDevice}},
+		bson.M{"$set": bson.M{"geo_location": location.Center, "geo_source": models.GeoSourceArea}},
+	)
+	return err
+}
+
+// GetLocations - GET /locations?type=county&parent=nairobi&q=west
+func GetLocations(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"is_active": true}
+
+	switch locationType := c.Query("type"); locationType {
+	case "":
+	case database.LocationCounty, database.LocationTown, database.LocationArea:
+		filter["type"] = locationType
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "type must be county, town or area"})
+		return
+	}
+
+	if parent := c.Query("parent"); parent != "" {
+		parentLocation, err := ResolveLocation(ctx, parent)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Parent location not found"})
+			return
+		}
+		filter["parent_id"] = parentLocation.ID
+	}
+
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		filter["keys"] = bson.M{"$regex": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.ToLower(q)), Options: ""}}
+	}
+
+	cursor, err := database.LocationCollection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "level", Value: 1}, {Key: "name", Value: 1}}).SetLimit(500),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch locations"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to read locations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(locations),
+		"data":    locations,
+	})
+}
+
+// GetLocation - GET /locations/:slug
+// Returns the location with its parents (broadest first) and direct children.
+func GetLocation(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	location, err := ResolveLocation(ctx, c.Param("slug"))
+	if err == ErrUnknownLocation {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Location not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch location"})
+		return
+	}
+
+	ancestors := []models.Location{}
+	if len(location.Ancestors) > 0 {
+		cursor, err := database.LocationCollection.Find(ctx,
+			bson.M{"_id": bson.M{"$in": location.Ancestors}},
+			options.Find().SetSort(bson.D{{Key: "level", Value: 1}}),
+		)
+		if err == nil {
+			cursor.All(ctx, &ancestors)
+		}
+	}
+
+	children := []models.Location{}
+	cursor, err := database.LocationCollection.Find(ctx,
+		bson.M{"parent_id": location.ID, "is_active": true},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	)
+	if err == nil {
+		cursor.All(ctx, &children)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"data":      location,
+		"ancestors": ancestors,
+		"children":  children,
+	})
+}
+
+// MigrateUserLocations links profiles saved with free-text locations to the
+// taxonomy. Locations that can't be matched are left for the user to correct.
+func MigrateUserLocations() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cursor, err := database.UserCollection.Find(ctx,
+		bson.M{"location_id": bson.M{"$exists": false}, "location": bson.M{"$nin": bson.A{"", nil}}},
+		options.Find().SetProjection(bson.M{"location": 1}),
+	)
+	if err != nil {
+		fmt.Printf("⚠️ Could not load users for location migration: %v\n", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	migrated, unmatched := 0, 0
+	for cursor.Next(ctx) {
+		var user struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Location string             `bson:"location"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			continue
+		}
+
+		location, err := resolveFreeTextLocation(ctx, user.Location)
+		if err != nil {
+			unmatched++
+			continue
+		}
+		if err := PlaceUserAt(ctx, user.ID, location); err != nil {
+			fmt.Printf("⚠️ Could not migrate location of user %s: %v\n", user.ID.Hex(), err)
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 || unmatched > 0 {
+		fmt.Printf("✅ Linked %d profiles to locations (%d could not be matched)\n", migrated, unmatched)
+	}
+}
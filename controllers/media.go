@@ -0,0 +1,357 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"escort/models"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxMediaUploadBytes = 10 << 20 // 10MB
+	maxAltTextLength    = 250
+	mediaFullSize       = 1600
+	mediaThumbnailSize  = 400
+	mediaMinDimension   = 200
+)
+
+var mediaCollection *mongo.Collection
+
+// mediaListSort orders the library newest first
+var mediaListSort = []sortKey{{Field: "_id", Desc: true}}
+
+func InitMediaCollection(client *mongo.Client) {
+	// Media lives next to the blogs it illustrates
+	db := client.Database("NairobiEscort")
+	mediaCollection = db.Collection("media")
+}
+
+// UploadMedia - POST /api/media (multipart: file, alt_text)
+func UploadMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMediaUploadBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image file is required"})
+		return
+	}
+	if fileHeader.Size > maxMediaUploadBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image must be 10MB or smaller"})
+		return
+	}
+
+	altText := strings.TrimSpace(c.PostForm("alt_text"))
+	if len(altText) > maxAltTextLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Alt text must be at most %d characters", maxAltTextLength)})
+		return
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	img, contentType, err := services.DecodeImage(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() < mediaMinDimension || bounds.Dy() < mediaMinDimension {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Image must be at least %dx%d pixels", mediaMinDimension, mediaMinDimension)})
+		return
+	}
+
+	// Re-encode both renditions, which also drops any embedded metadata
+	full := services.ResizeToFit(img, mediaFullSize)
+	fullBytes, err := services.EncodeJPEG(full, 85)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process image"})
+		return
+	}
+	thumbBytes, err := services.EncodeJPEG(services.ResizeToFit(img, mediaThumbnailSize), 80)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process image"})
+		return
+	}
+
+	storage, err := services.NewObjectStore(services.MediaBucket())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Storage configuration missing"})
+		return
+	}
+
+	item := models.MediaItem{
+		ID:           primitive.NewObjectID(),
+		AltText:      altText,
+		OriginalName: fileHeader.Filename,
+		ContentType:  contentType,
+		Width:        full.Bounds().Dx(),
+		Height:       full.Bounds().Dy(),
+		Size:         int64(len(fullBytes)),
+		UploadedBy:   c.GetString("userID"),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	item.StoragePath = "blog/" + item.ID.Hex()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	fullKey := item.StoragePath + "/full.jpg"
+	thumbKey := item.StoragePath + "/thumb.jpg"
+
+	if err := storage.Put(ctx, fullKey, bytes.NewReader(fullBytes), int64(len(fullBytes)), "image/jpeg"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image: " + err.Error()})
+		return
+	}
+	if err := storage.Put(ctx, thumbKey, bytes.NewReader(thumbBytes), int64(len(thumbBytes)), "image/jpeg"); err != nil {
+		storage.Delete(ctx, fullKey)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image: " + err.Error()})
+		return
+	}
+	item.URL = storage.PublicURL(fullKey)
+	item.ThumbnailURL = storage.PublicURL(thumbKey)
+
+	if _, err := mediaCollection.InsertOne(ctx, item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save media item"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": item})
+}
+
+// ListMedia - GET /api/media?limit=&cursor=
+func ListMedia(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	limit := parseLimit(c, 24, 100)
+	filter := bson.M{}
+
+	if token := c.Query("cursor"); token != "" {
+		values, err := decodeCursor(token, len(mediaListSort))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter = afterCursorFilter(mediaListSort, values)
+	}
+
+	findOptions := options.Find().
+		SetSort(sortKeysDoc(mediaListSort)).
+		SetLimit(int64(limit + 1))
+
+	cursor, err := mediaCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.MediaItem{}
+	if err = cursor.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse media"})
+		return
+	}
+
+	hasMore := len(items) > limit
+	nextCursor := ""
+	if hasMore {
+		items = items[:limit]
+		nextCursor = encodeCursor(bson.A{items[len(items)-1].ID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        items,
+		"next_cursor": nextCursor,
+		"has_more":    hasMore,
+	})
+}
+
+// GetMedia - GET /api/media/:id
+func GetMedia(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var item models.MediaItem
+	err = mediaCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	usage, _ := blogCollection.CountDocuments(ctx, bson.M{"image_id": objID})
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": item, "used_by": usage})
+}
+
+// UpdateMedia - PUT /api/media/:id (alt text only; the image itself is immutable)
+func UpdateMedia(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	var request struct {
+		AltText string `json:"alt_text"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	request.AltText = strings.TrimSpace(request.AltText)
+	if len(request.AltText) > maxAltTextLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Alt text must be at most %d characters", maxAltTextLength)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := mediaCollection.UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$set": bson.M{"alt_text": request.AltText, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update media"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+
+	// Keep the copy on blogs using this image in sync
+	if _, err := blogCollection.UpdateMany(ctx,
+		bson.M{"image_id": objID},
+		bson.M{"$set": bson.M{"image_alt": request.AltText}},
+	); err != nil {
+		log.Printf("⚠️ Warning: Could not update alt text on blogs: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Media updated successfully"})
+}
+
+// DeleteMedia - DELETE /api/media/:id?force=true
+// Images used as a blog cover are only deleted with force, which also clears the cover.
+func DeleteMedia(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var item models.MediaItem
+	err = mediaCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	usage, err := blogCollection.CountDocuments(ctx, bson.M{"image_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check media usage"})
+		return
+	}
+	if usage > 0 && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Media is used by blog posts",
+			"used_by": usage,
+		})
+		return
+	}
+
+	if usage > 0 {
+		if _, err := blogCollection.UpdateMany(ctx,
+			bson.M{"image_id": objID},
+			bson.M{"$set": bson.M{"image": ""}, "$unset": bson.M{"image_id": "", "image_alt": ""}},
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detach media from blogs"})
+			return
+		}
+	}
+
+	storage, err := services.NewObjectStore(services.MediaBucket())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Storage configuration missing"})
+		return
+	}
+	for _, name := range []string{"full.jpg", "thumb.jpg"} {
+		if err := storage.Delete(ctx, item.StoragePath+"/"+name); err != nil {
+			log.Printf("⚠️ Warning: Could not delete %s/%s: %v", item.StoragePath, name, err)
+		}
+	}
+
+	if _, err := mediaCollection.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete media"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Media deleted successfully"})
+}
+
+// resolveBlogImage sets the cover image fields of blog from its media library reference.
+// Legacy posts with a plain URL and no reference keep their existing image.
+func resolveBlogImage(ctx context.Context, blog *models.Blog, existing *models.Blog) error {
+	if blog.ImageID == nil {
+		blog.Image = ""
+		blog.ImageAlt = ""
+		if existing != nil && existing.ImageID == nil {
+			blog.Image = existing.Image
+		}
+		return nil
+	}
+
+	var item models.MediaItem
+	err := mediaCollection.FindOne(ctx, bson.M{"_id": *blog.ImageID}).Decode(&item)
+	if err != nil {
+		return err
+	}
+
+	blog.Image = item.URL
+	blog.ImageAlt = item.AltText
+	return nil
+}
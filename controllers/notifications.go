@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetNotifications - GET /auth/notifications?unread=true&limit=
+func GetNotifications(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userObjID}
+	if c.Query("unread") == "true" {
+		filter["read"] = false
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(parseLimit(c, 20, 100)))
+
+	cursor, err := database.NotificationCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read notifications"})
+		return
+	}
+
+	unread, _ := database.NotificationCollection.CountDocuments(ctx, bson.M{"user_id": userObjID, "read": false})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"data":         notifications,
+		"unread_count": unread,
+	})
+}
+
+// MarkNotificationsRead - PUT /auth/notifications/read {"ids": [...]}; without ids every notification is marked read
+func MarkNotificationsRead(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var request struct {
+		IDs []string `json:"ids"`
+	}
+	// An empty body means "mark all"
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			return
+		}
+	}
+
+	filter := bson.M{"user_id": userObjID, "read": false}
+	if len(request.IDs) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(request.IDs))
+		for _, id := range request.IDs {
+			objID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID: " + id})
+				return
+			}
+			ids = append(ids, objID)
+		}
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.NotificationCollection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"updated": result.ModifiedCount,
+	})
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// sortKey is one field of a cursor-paginated sort order
+type sortKey struct {
+	Field string
+	Desc  bool
+}
+
+// sortKeysDoc converts sort keys into a Mongo sort document
+func sortKeysDoc(keys []sortKey) bson.D {
+	sort := bson.D{}
+	for _, key := range keys {
+		direction := 1
+		if key.Desc {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: key.Field, Value: direction})
+	}
+	return sort
+}
+
+// encodeCursor packs the sort values of the last returned item into an opaque token
+func encodeCursor(values bson.A) string {
+	data, err := bson.Marshal(bson.D{{Key: "v", Value: values}})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor unpacks a token produced by encodeCursor
+func decodeCursor(token string, keyCount int) (bson.A, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var doc struct {
+		V bson.A `bson:"v"`
+	}
+	if err := bson.Unmarshal(data, &doc); err != nil || len(doc.V) != keyCount {
+		return nil, errors.New("invalid cursor")
+	}
+	return doc.V, nil
+}
+
+// afterCursorFilter selects documents that sort strictly after the cursor values.
+// The last sort key must be unique (normally _id) so pages never overlap.
+func afterCursorFilter(keys []sortKey, values bson.A) bson.M {
+	var branches []bson.M
+	for i, key := range keys {
+		branch := bson.M{}
+		for j := 0; j < i; j++ {
+			branch[keys[j].Field] = values[j]
+		}
+
+		op := "$gt"
+		if key.Desc {
+			op = "$lt"
+		}
+		branch[key.Field] = bson.M{op: values[i]}
+		branches = append(branches, branch)
+	}
+	return bson.M{"$or": branches}
+}
+
+// parseLimit reads the "limit" query parameter, falling back to def and capping at max
+func parseLimit(c *gin.Context, def, max int) int {
+	return limitValue(c.Query("limit"), def, max)
+}
+
+// limitValue parses a page size, falling back to def and capping at max
+func limitValue(value string, def, max int) int {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return def
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
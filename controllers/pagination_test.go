@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	createdAt := primitive.NewDateTimeFromTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name   string
+		values bson.A
+	}{
+		{"id only", bson.A{id}},
+		{"date and id", bson.A{createdAt, id}},
+		{"mixed types", bson.A{"kilimani", 1.5, int32(3), int64(7), true, nil, id}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodeCursor(tt.values)
+			if token == "" {
+				t.Fatal("encodeCursor returned an empty token")
+			}
+			got, err := decodeCursor(token, len(tt.values))
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.values) {
+				t.Errorf("decodeCursor = %#v, want %#v", got, tt.values)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	valid := encodeCursor(bson.A{primitive.NewObjectID(), int32(2)})
+
+	tests := []struct {
+		name     string
+		token    string
+		keyCount int
+	}{
+		{"empty", "", 1},
+		{"not base64", "not a cursor!", 1},
+		{"not bson", "aGVsbG8", 1},
+		{"padded base64", valid + "==", 2},
+		{"fewer values than keys", valid, 3},
+		{"more values than keys", valid, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.token, tt.keyCount); err == nil {
+				t.Errorf("decodeCursor(%q, %d) succeeded", tt.token, tt.keyCount)
+			}
+		})
+	}
+}
+
+func TestApplyCursor(t *testing.T) {
+	keys := []sortKey{{Field: "created_at", Desc: true}, {Field: "_id", Desc: true}}
+	createdAt := primitive.NewDateTimeFromTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name          string
+		values        bson.A
+		wantErr       bool
+		wantPage      int
+		wantWindow    int64
+		wantFeatured  int
+		wantAfterSize int
+	}{
+		{"current cursor", bson.A{createdAt, id, int32(3), int64(1900000), int32(8)}, false, 3, 1900000, 8, 2},
+		{"cursor without featured state", bson.A{createdAt, id, int32(2)}, false, 2, 0, 0, 2},
+		{"zero page", bson.A{createdAt, id, int32(0), int64(1), int32(0)}, true, 0, 0, 0, 0},
+		{"negative featured count", bson.A{createdAt, id, int32(2), int64(1), int32(-1)}, true, 0, 0, 0, 0},
+		{"page of the wrong type", bson.A{createdAt, id, "2"}, true, 0, 0, 0, 0},
+		{"sort values missing", bson.A{id, int32(2)}, true, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ListingQuery{Page: 1, Cursor: encodeCursor(tt.values)}
+			err := q.applyCursor(keys)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("applyCursor succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyCursor: %v", err)
+			}
+			if q.Page != tt.wantPage || q.window != tt.wantWindow || q.featuredShown != tt.wantFeatured || len(q.after) != tt.wantAfterSize {
+				t.Errorf("applyCursor gave page %d, window %d, featured %d, %d sort values; want %d, %d, %d, %d",
+					q.Page, q.window, q.featuredShown, len(q.after), tt.wantPage, tt.wantWindow, tt.wantFeatured, tt.wantAfterSize)
+			}
+		})
+	}
+}
+
+func TestNextCursorCarriesListingState(t *testing.T) {
+	keys := []sortKey{{Field: "_id", Desc: true}}
+	id := primitive.NewObjectID()
+	q := ListingQuery{Page: 2, window: 1900000, featuredShown: 4, featuredOnPage: 3}
+
+	next := ListingQuery{Page: 1, Cursor: q.nextCursor(bson.A{id})}
+	if err := next.applyCursor(keys); err != nil {
+		t.Fatalf("applyCursor: %v", err)
+	}
+	if next.Page != 3 || next.window != 1900000 || next.featuredShown != 7 || next.after[0] != id {
+		t.Errorf("next page is %d, window %d, featured %d, after %v", next.Page, next.window, next.featuredShown, next.after)
+	}
+}
+
+func TestLimitValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", 20},
+		{"10", 10},
+		{"1", 1},
+		{"100", 100},
+		{"101", 100},
+		{"0", 20},
+		{"-5", 20},
+		{"ten", 20},
+		{"2.5", 20},
+	}
+	for _, tt := range tests {
+		if got := limitValue(tt.value, 20, 100); got != tt.want {
+			t.Errorf("limitValue(%q, 20, 100) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		url  string
+		want int
+	}{
+		{"/profiles", 12},
+		{"/profiles?limit=30", 30},
+		{"/profiles?limit=500", 50},
+		{"/profiles?limit=abc", 12},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", tt.url, nil)
+		if got := parseLimit(c, 12, 50); got != tt.want {
+			t.Errorf("parseLimit(%s) = %d, want %d", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateHashDistance is the largest hash distance still treated as the same photo
+const duplicateHashDistance = 10
+
+// maxDuplicatesPerImage keeps the stored matches of widely reused photos bounded
+const maxDuplicatesPerImage = 20
+
+// duplicateHashBands is how many bands a hash is indexed by. One more than the
+// largest duplicate distance means every duplicate shares at least one band.
+const duplicateHashBands = duplicateHashDistance + 1
+
+// flagDuplicateImages looks up photos owned by other accounts that share a hash
+// band with images and records the near-identical ones on each image
+func flagDuplicateImages(ctx context.Context, userObjID primitive.ObjectID, images []models.ProfileImage) error {
+	hashes := map[int]uint64{}
+	var bands []string
+	for i, image := range images {
+		if hash, err := services.ParsePerceptualHash(image.Hash); err == nil {
+			hashes[i] = hash
+			bands = append(bands, services.PerceptualHashBands(hash, duplicateHashBands)...)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	findOptions := options.Find().SetProjection(bson.M{"user_id": 1, "image_id": 1, "phash": 1})
+	cursor, err := database.ImageHashCollection.Find(ctx, bson.M{
+		"user_id": bson.M{"$ne": userObjID},
+		"bands":   bson.M{"$in": bands},
+	}, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var other models.ImageHash
+		if err := cursor.Decode(&other); err != nil {
+			continue
+		}
+		otherHash, err := services.ParsePerceptualHash(other.Hash)
+		if err != nil {
+			continue
+		}
+
+		// Sharing a band only makes it a candidate
+		for i, hash := range hashes {
+			distance := services.HashDistance(hash, otherHash)
+			if distance > duplicateHashDistance {
+				continue
+			}
+			images[i].Duplicates = append(images[i].Duplicates, models.ImageDuplicate{
+				UserID:   other.UserID,
+				ImageID:  other.ImageID,
+				Distance: distance,
+			})
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	// Closest matches first
+	for i := range images {
+		duplicates := images[i].Duplicates
+		sort.SliceStable(duplicates, func(a, b int) bool { return duplicates[a].Distance < duplicates[b].Distance })
+		if len(duplicates) > maxDuplicatesPerImage {
+			images[i].Duplicates = duplicates[:maxDuplicatesPerImage]
+		}
+	}
+	return nil
+}
+
+// RecordImageHashes indexes the hashes of a user's photos so later uploads are
+// checked against them. Recording a photo twice keeps one entry.
+func RecordImageHashes(ctx context.Context, userObjID primitive.ObjectID, images []models.ProfileImage) error {
+	var writes []mongo.WriteModel
+	for _, image := range images {
+		hash, err := services.ParsePerceptualHash(image.Hash)
+		if err != nil || image.ID.IsZero() {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"image_id": image.ID}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"user_id": userObjID,
+					"phash":   image.Hash,
+					"bands":   services.PerceptualHashBands(hash, duplicateHashBands),
+				},
+				"$setOnInsert": bson.M{"created_at": time.Now()},
+			}).
+			SetUpsert(true))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	_, err := database.ImageHashCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
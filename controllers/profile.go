@@ -49,6 +49,7 @@ func DeleteUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting user"})
 		return
 	}
+	database.ImageHashCollection.DeleteMany(context.TODO(), bson.M{"user_id": objID})
 
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
@@ -0,0 +1,723 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxProfileImages     = 5
+	maxProfileImageBytes = 10 << 20 // per photo
+)
+
+// profileRenditions are the sizes every profile photo is re-encoded to
+var profileRenditions = []struct {
+	name      string
+	maxSize   int
+	quality   int
+	watermark bool // public renditions large enough to be worth scraping
+}{
+	{"thumbnail", 320, 80, false},
+	{"card", 800, 82, true},
+	{"full", 1600, 85, true},
+}
+
+// profileOriginalSize caps the unwatermarked copy kept for moderators
+const profileOriginalSize = 3000
+
+// errInvalidProfileImage marks upload failures caused by the file itself
+var errInvalidProfileImage = errors.New("invalid image")
+
+// errTooManyPhotos is returned when attaching photos would exceed maxProfileImages
+var errTooManyPhotos = fmt.Errorf("maximum %d photos allowed", maxProfileImages)
+
+// userPhotos is the photo state of a user
+type userPhotos struct {
+	Images         []models.ProfileImage `bson:"images"`
+	PrimaryImageID *primitive.ObjectID   `bson:"primary_image_id"`
+}
+
+// loadUserPhotos reads the photos of a user
+func loadUserPhotos(ctx context.Context, userObjID primitive.ObjectID) (*userPhotos, error) {
+	var photos userPhotos
+	err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&photos)
+	if err != nil {
+		return nil, err
+	}
+	return &photos, nil
+}
+
+// indexOf finds a photo by its hex ID, returning -1 when missing
+func (p *userPhotos) indexOf(imageID string) int {
+	for i, image := range p.Images {
+		if image.ID.Hex() == imageID {
+			return i
+		}
+	}
+	return -1
+}
+
+// updatePhotos applies update to the user's photos when filter still matches
+// them, then returns the photos as they are now. Updates touch only the photos
+// they are about, so concurrent uploads, edits, moderation and duplicate flagging
+// don't overwrite each other. It fails with mongo.ErrNoDocuments when filter
+// matches nothing.
+func updatePhotos(ctx context.Context, userObjID primitive.ObjectID, filter bson.M, update interface{}, opts ...*options.UpdateOptions) (*userPhotos, error) {
+	filter["_id"] = userObjID
+	result, err := database.UserCollection.UpdateOne(ctx, filter, update, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return syncCoverImage(ctx, userObjID)
+}
+
+// syncCoverImage reloads the user's photos and keeps image_url in sync with the
+// public cover photo, forgetting a chosen cover photo that has been deleted
+func syncCoverImage(ctx context.Context, userObjID primitive.ObjectID) (*userPhotos, error) {
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+	if photos.Images == nil {
+		photos.Images = []models.ProfileImage{}
+	}
+
+	if photos.PrimaryImageID != nil && photos.indexOf(photos.PrimaryImageID.Hex()) < 0 {
+		database.UserCollection.UpdateOne(ctx,
+			bson.M{"_id": userObjID, "primary_image_id": *photos.PrimaryImageID},
+			bson.M{"$unset": bson.M{"primary_image_id": ""}},
+		)
+		photos.PrimaryImageID = nil
+	}
+
+	_, err = database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$set": bson.M{
+			"image_url":  models.CoverImageURL(photos.Images, photos.PrimaryImageID),
+			"updated_at": time.Now(),
+		}},
+	)
+	return photos, err
+}
+
+// response is the photo part of every photo endpoint response
+func (p *userPhotos) response() gin.H {
+	return gin.H{
+		"success":          true,
+		"images":           p.Images,
+		"primary_image_id": p.PrimaryImageID,
+		"image_url":        models.CoverImageURL(p.Images, p.PrimaryImageID),
+	}
+}
+
+// processProfileImage validates an uploaded photo, strips its metadata by
+// re-encoding and stores every rendition under <userID>/<imageID>/. An
+// unwatermarked original goes to the private originals store.
+func processProfileImage(ctx context.Context, storage, originals services.ObjectStore, userID string, data []byte, watermark *profileWatermark) (models.ProfileImage, error) {
+	img, _, err := services.DecodeImage(data)
+	if err != nil {
+		return models.ProfileImage{}, fmt.Errorf("%w: %v", errInvalidProfileImage, err)
+	}
+
+	// Phones store rotation as an EXIF tag, which re-encoding drops
+	orientation := services.JPEGOrientation(data)
+
+	// New photos stay hidden until an admin approves them
+	image := models.ProfileImage{
+		ID:        primitive.NewObjectID(),
+		CreatedAt: time.Now(),
+		Status:    models.ImageStatusPending,
+	}
+	image.Path = userID + "/" + image.ID.Hex() + "/"
+
+	original, err := services.EncodeJPEG(services.ApplyOrientation(services.ResizeToFit(img, profileOriginalSize), orientation), 90)
+	if err != nil {
+		return image, err
+	}
+	// Originals sit next to the public renditions' path, so the name must not be guessable
+	secret, err := services.RandomKeySegment()
+	if err != nil {
+		return image, err
+	}
+	image.OriginalKey = services.ProfileOriginalsPrefix + image.Path + "original-" + secret + ".jpg"
+	if err := originals.Put(ctx, image.OriginalKey, bytes.NewReader(original), int64(len(original)), "image/jpeg"); err != nil {
+		return image, err
+	}
+
+	for _, rendition := range profileRenditions {
+		resized := services.ApplyOrientation(services.ResizeToFit(img, rendition.maxSize), orientation)
+
+		// The hash is taken before watermarking so stamped copies still match
+		if rendition.name == "thumbnail" {
+			image.Hash = services.FormatPerceptualHash(services.PerceptualHash(resized))
+		}
+
+		stamped := resized
+		if rendition.watermark && watermark != nil {
+			stamped = services.Watermark(resized, watermark.text, watermark.position)
+		}
+		encoded, err := services.EncodeJPEG(stamped, rendition.quality)
+		if err != nil {
+			return image, err
+		}
+
+		key := image.Path + rendition.name + ".jpg"
+		if err := storage.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), "image/jpeg"); err != nil {
+			return image, err
+		}
+
+		url := storage.PublicURL(key)
+		switch rendition.name {
+		case "thumbnail":
+			image.Thumbnail = url
+		case "card":
+			image.Card = url
+		case "full":
+			image.Full = url
+			image.Width = resized.Bounds().Dx()
+			image.Height = resized.Bounds().Dy()
+		}
+	}
+
+	return image, nil
+}
+
+// deleteProfileImageObjects removes the stored renditions and originals of
+// images; failures are left for the orphaned image cleanup job
+func deleteProfileImageObjects(ctx context.Context, storage services.ObjectStore, images []models.ProfileImage) {
+	var urls []string
+	for _, image := range images {
+		urls = append(urls, image.URLs()...)
+	}
+	services.DeleteObjectsByURL(ctx, storage, urls)
+
+	originals, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		return
+	}
+	for _, image := range images {
+		if image.OriginalKey == "" {
+			continue
+		}
+		if err := originals.Delete(ctx, image.OriginalKey); err != nil {
+			fmt.Printf("⚠️ Could not delete original %s: %v\n", image.OriginalKey, err)
+		}
+	}
+}
+
+// photoUploader turns uploaded files into stored profile photos of one user
+type photoUploader struct {
+	userObjID primitive.ObjectID
+	storage   services.ObjectStore
+	originals services.ObjectStore
+	watermark *profileWatermark
+}
+
+// newPhotoUploader prepares the stores and watermark for a user's uploads
+func newPhotoUploader(ctx context.Context, userObjID primitive.ObjectID) (*photoUploader, error) {
+	storage, err := services.NewObjectStore(services.ProfileBucket())
+	if err != nil {
+		return nil, errors.New("Storage configuration missing")
+	}
+	originals, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		return nil, errors.New("Storage configuration missing")
+	}
+
+	watermark, err := userWatermark(ctx, userObjID)
+	if err != nil {
+		return nil, errors.New("Failed to load watermark settings")
+	}
+
+	return &photoUploader{
+		userObjID: userObjID,
+		storage:   storage,
+		originals: originals,
+		watermark: watermark,
+	}, nil
+}
+
+// process stores the renditions of one uploaded file
+func (u *photoUploader) process(ctx context.Context, data []byte) (models.ProfileImage, error) {
+	return processProfileImage(ctx, u.storage, u.originals, u.userObjID.Hex(), data, u.watermark)
+}
+
+// discard deletes the stored files of photos that won't be attached
+func (u *photoUploader) discard(ctx context.Context, images []models.ProfileImage) {
+	deleteProfileImageObjects(ctx, u.storage, images)
+}
+
+// attach flags duplicates among uploaded and adds them to the user's photos
+func (u *photoUploader) attach(ctx context.Context, photos *userPhotos, uploaded []models.ProfileImage) error {
+	// Flag photos already used by other accounts for the moderators
+	if err := flagDuplicateImages(ctx, u.userObjID, uploaded); err != nil {
+		log.Printf("⚠️ Warning: Duplicate photo check failed: %v", err)
+	}
+
+	// Only add them while there is room; other uploads may have finished meanwhile
+	updated, err := updatePhotos(ctx, u.userObjID,
+		bson.M{"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$size": bson.M{"$ifNull": bson.A{"$images", bson.A{}}}},
+			maxProfileImages - len(uploaded),
+		}}},
+		bson.M{"$push": bson.M{"images": bson.M{"$each": uploaded}}},
+	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = errTooManyPhotos
+	}
+	if err != nil {
+		u.discard(ctx, uploaded)
+		return err
+	}
+	*photos = *updated
+
+	if err := RecordImageHashes(ctx, u.userObjID, uploaded); err != nil {
+		log.Printf("⚠️ Warning: Could not record photo hashes: %v", err)
+	}
+	return nil
+}
+
+// MigrateProfileImages converts photos stored as plain URL strings into
+// structured images so every photo has a stable ID
+func MigrateProfileImages() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	cursor, err := database.UserCollection.Find(ctx, bson.M{"images": bson.M{"$type": "string"}})
+	if err != nil {
+		log.Printf("⚠️ Warning: Could not migrate profile images: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	storage, storeErr := services.NewObjectStore(services.ProfileBucket())
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var user struct {
+			ID        primitive.ObjectID    `bson:"_id"`
+			Images    []models.ProfileImage `bson:"images"`
+			UpdatedAt time.Time             `bson:"updated_at"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			continue
+		}
+
+		for i := range user.Images {
+			if !user.Images[i].ID.IsZero() {
+				continue
+			}
+			user.Images[i].ID = primitive.NewObjectID()
+			user.Images[i].CreatedAt = user.UpdatedAt
+			if storeErr == nil {
+				user.Images[i].Path, _ = storage.KeyFromURL(user.Images[i].Full)
+			}
+		}
+
+		_, err := database.UserCollection.UpdateOne(ctx,
+			bson.M{"_id": user.ID},
+			bson.M{"$set": bson.M{"images": user.Images}},
+		)
+		if err != nil {
+			log.Printf("⚠️ Warning: Could not migrate images of user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		fmt.Printf("🖼️ Migrated profile images of %d users\n", migrated)
+	}
+}
+
+// UploadProfileImages - POST /auth/upload-images
+func UploadProfileImages(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxProfileImages*maxProfileImageBytes+1<<20)
+
+	// Parse multipart form
+	err := c.Request.ParseMultipartForm(maxProfileImageBytes)
+	if err != nil {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Upload is too large or malformed. Photos must be %dMB or smaller", maxProfileImageBytes>>20)})
+		return
+	}
+
+	// Get files from form
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Failed to get multipart form: " + err.Error()})
+		return
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		c.JSON(400, gin.H{"error": "No images provided"})
+		return
+	}
+
+	// Limit to 5 files per upload
+	if len(files) > maxProfileImages {
+		files = files[:maxProfileImages]
+	}
+
+	for _, file := range files {
+		if file.Size > maxProfileImageBytes {
+			c.JSON(400, gin.H{"error": fmt.Sprintf("%s is larger than %dMB", file.Filename, maxProfileImageBytes>>20)})
+			return
+		}
+	}
+
+	userObjID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	// Get current user to check existing images
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to fetch user: " + err.Error()})
+		return
+	}
+
+	// Check if adding new files would exceed 5 total
+	if len(photos.Images)+len(files) > maxProfileImages {
+		c.JSON(400, gin.H{
+			"error": fmt.Sprintf("Maximum 5 photos allowed. You have %d photos, trying to add %d more",
+				len(photos.Images), len(files)),
+		})
+		return
+	}
+
+	uploader, err := newPhotoUploader(ctx, userObjID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	var uploaded []models.ProfileImage
+
+	for _, file := range files {
+		src, err := file.Open()
+		if err != nil {
+			uploader.discard(ctx, uploaded)
+			c.JSON(500, gin.H{"error": "Failed to open file: " + err.Error()})
+			return
+		}
+		data, err := io.ReadAll(src)
+		src.Close()
+		if err != nil {
+			uploader.discard(ctx, uploaded)
+			c.JSON(500, gin.H{"error": "Failed to read file: " + err.Error()})
+			return
+		}
+
+		image, err := uploader.process(ctx, data)
+		if err != nil {
+			uploader.discard(ctx, append(uploaded, image))
+			if errors.Is(err, errInvalidProfileImage) {
+				c.JSON(400, gin.H{"error": file.Filename + ": " + err.Error()})
+				return
+			}
+			c.JSON(500, gin.H{"error": "Failed to upload image: " + err.Error()})
+			return
+		}
+
+		fmt.Printf("✅ Image upload success! ID: %s\n", image.ID.Hex())
+		uploaded = append(uploaded, image)
+	}
+
+	if err := uploader.attach(ctx, photos, uploaded); err != nil {
+		if errors.Is(err, errTooManyPhotos) {
+			c.JSON(400, gin.H{"error": fmt.Sprintf("Maximum %d photos allowed", maxProfileImages)})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Failed to update user: " + err.Error()})
+		return
+	}
+
+	response := photos.response()
+	response["message"] = "Images uploaded successfully and are awaiting review"
+	response["uploaded"] = uploaded
+	// Deprecated: card URLs of the new photos, for clients that predate "uploaded"
+	imageURLs := make([]string, 0, len(uploaded))
+	for _, image := range uploaded {
+		imageURLs = append(imageURLs, image.Card)
+	}
+	response["imageUrls"] = imageURLs
+	response["totalImages"] = len(photos.Images)
+	response["maxAllowed"] = maxProfileImages
+	c.JSON(200, response)
+}
+
+// DeleteProfileImage - DELETE /auth/delete-image
+func DeleteProfileImage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	// Photos are identified by ID; imageUrl (any rendition) is still accepted
+	var request struct {
+		ImageID  string `json:"imageId"`
+		ImageURL string `json:"imageUrl"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	if request.ImageID == "" && request.ImageURL == "" {
+		c.JSON(400, gin.H{"error": "Image ID is required"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// First, get current user to check images
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to fetch user: " + err.Error()})
+		return
+	}
+
+	// Check if image exists in user's images
+	imageIndex := -1
+	for i, img := range photos.Images {
+		if (request.ImageID != "" && img.ID.Hex() == request.ImageID) || img.HasURL(request.ImageURL) {
+			imageIndex = i
+			break
+		}
+	}
+
+	if imageIndex == -1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found in user's photos"})
+		return
+	}
+
+	removed := photos.Images[imageIndex]
+
+	// Remove image from array; the cover photo falls back to the first remaining one
+	photos, err = updatePhotos(ctx, userObjID,
+		bson.M{"images.id": removed.ID},
+		bson.M{"$pull": bson.M{"images": bson.M{"id": removed.ID}}},
+	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found in user's photos"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update user: " + err.Error()})
+		return
+	}
+
+	// Remove the files themselves; failures are left for the orphaned image cleanup job
+	if storage, err := services.NewObjectStore(services.ProfileBucket()); err == nil {
+		deleteProfileImageObjects(ctx, storage, []models.ProfileImage{removed})
+	}
+	database.ImageHashCollection.DeleteOne(ctx, bson.M{"image_id": removed.ID})
+
+	response := photos.response()
+	response["message"] = "Image deleted successfully"
+	response["remainingImages"] = len(photos.Images)
+	c.JSON(200, response)
+}
+
+// ReorderProfileImages - PUT /auth/images/order {"image_ids": [...]}
+func ReorderProfileImages(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var request struct {
+		ImageIDs []string `json:"image_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image_ids is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	// The new order must list every photo exactly once
+	if len(request.ImageIDs) != len(photos.Images) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("image_ids must list all %d photos", len(photos.Images))})
+		return
+	}
+	if len(photos.Images) == 0 {
+		c.JSON(http.StatusOK, photos.response())
+		return
+	}
+	ordered := make([]primitive.ObjectID, 0, len(photos.Images))
+	seen := map[string]bool{}
+	for _, imageID := range request.ImageIDs {
+		i := photos.indexOf(imageID)
+		if i < 0 || seen[imageID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown or repeated image ID: " + imageID})
+			return
+		}
+		seen[imageID] = true
+		ordered = append(ordered, photos.Images[i].ID)
+	}
+
+	// Rearrange the stored photos themselves, so changes made to them since they
+	// were loaded are kept; the order only applies if the set of photos is unchanged
+	photos, err = updatePhotos(ctx, userObjID,
+		bson.M{"images": bson.M{"$size": len(ordered)}, "images.id": bson.M{"$all": ordered}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"images": bson.M{"$map": bson.M{
+			"input": ordered,
+			"as":    "id",
+			"in": bson.M{"$arrayElemAt": bson.A{
+				bson.M{"$filter": bson.M{"input": "$images", "cond": bson.M{"$eq": bson.A{"$$this.id", "$$id"}}}},
+				0,
+			}},
+		}}}}}},
+	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your photos changed meanwhile; reload them and try again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save photo order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, photos.response())
+}
+
+// SetPrimaryProfileImage - PUT /auth/images/:id/primary
+func SetPrimaryProfileImage(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	i := photos.indexOf(c.Param("id"))
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found in user's photos"})
+		return
+	}
+	primaryID := photos.Images[i].ID
+
+	photos, err = updatePhotos(ctx, userObjID,
+		bson.M{"images.id": primaryID},
+		bson.M{"$set": bson.M{"primary_image_id": primaryID}},
+	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found in user's photos"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set primary photo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, photos.response())
+}
+
+// UpdateProfileImageCaption - PUT /auth/images/:id/caption {"caption": "..."}
+func UpdateProfileImageCaption(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var request struct {
+		Caption string `json:"caption"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	caption := strings.TrimSpace(request.Caption)
+	if utf8.RuneCountInString(caption) > models.MaxImageCaptionLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Caption must be at most %d characters", models.MaxImageCaptionLength)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	i := photos.indexOf(c.Param("id"))
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found in user's photos"})
+		return
+	}
+	imageID := photos.Images[i].ID
+
+	photos, err = updatePhotos(ctx, userObjID,
+		bson.M{"images.id": imageID},
+		bson.M{"$set": bson.M{"images.$.caption": caption}},
+	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found in user's photos"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save caption"})
+		return
+	}
+
+	c.JSON(http.StatusOK, photos.response())
+}
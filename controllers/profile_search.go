@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"escort/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxSearchTerms         = 8
+	minPrefixLength        = 3  // Shorter terms only match whole words
+	maxTermExpansions      = 12 // Vocabulary words a single term may expand to
+	searchVocabularyTTL    = 5 * time.Minute
+	featuredRelevanceBoost = 1.5 // Relevance multiplier for profiles with a running subscription
+)
+
+// profileTextFields are the profile fields covered by the profile_text index
+var profileTextFields = []string{"first_name", "last_name", "location", "services"}
+
+// searchTerms splits user input into lowercase words. Everything other than
+// letters and digits is dropped, so no operator or pattern syntax survives.
+func searchTerms(text string) []string {
+	if runes := []rune(text); len(runes) > maxSearchQueryLength {
+		text = string(runes[:maxSearchQueryLength])
+	}
+
+	var terms []string
+	seen := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// searchVocabulary caches the words used by visible profiles, which search
+// terms are expanded against for prefix and typo-tolerant matching
+var searchVocabulary = struct {
+	sync.Mutex
+	words     []string
+	expiresAt time.Time
+}{}
+
+// loadSearchVocabulary returns the sorted, distinct words of the searchable
+// fields of visible profiles
+func loadSearchVocabulary(ctx context.Context) ([]string, error) {
+	now := time.Now()
+
+	searchVocabulary.Lock()
+	defer searchVocabulary.Unlock()
+	if now.Before(searchVocabulary.expiresAt) {
+		return searchVocabulary.words, nil
+	}
+
+	seen := map[string]bool{}
+	for _, field := range profileTextFields {
+		values, err := database.UserCollection.Distinct(ctx, field, visibleProfileFilter(now))
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			text, ok := value.(string)
+			if !ok {
+				continue
+			}
+			for _, word := range searchTerms(text) {
+				if len(word) >= 2 {
+					seen[word] = true
+				}
+			}
+		}
+	}
+
+	words := make([]string, 0, len(seen))
+	for word := range seen {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	searchVocabulary.words = words
+	searchVocabulary.expiresAt = now.Add(searchVocabularyTTL)
+	return words, nil
+}
+
+// expandTerm lists the vocabulary words a search term stands for: the term itself,
+// words it is a prefix of, and words within a small edit distance of it
+func expandTerm(term string, vocabulary []string) []string {
+	expanded := []string{term}
+	add := func(word string) bool {
+		if word != term && len(expanded) < maxTermExpansions {
+			expanded = append(expanded, word)
+		}
+		return len(expanded) < maxTermExpansions
+	}
+
+	if len(term) >= minPrefixLength {
+		start := sort.SearchStrings(vocabulary, term)
+		for _, word := range vocabulary[start:] {
+			if !strings.HasPrefix(word, term) || !add(word) {
+				break
+			}
+		}
+	}
+
+	if edits := maxTypos(term); edits > 0 {
+		for _, word := range vocabulary {
+			if strings.HasPrefix(word, term) {
+				continue // Already added as a prefix match
+			}
+			if editDistance(term, word, edits) <= edits && !add(word) {
+				break
+			}
+		}
+	}
+	return expanded
+}
+
+// maxTypos is how many edits a term tolerates; short terms must be exact
+func maxTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// editDistance is the Levenshtein distance between a and b, or max+1 as soon
+// as it's known to exceed max
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// textSearch is a parsed profile search: for every term of the query, the
+// words it matches
+type textSearch [][]string
+
+// parseTextSearch splits text into terms and expands each against the vocabulary
+func parseTextSearch(ctx context.Context, text string) (textSearch, error) {
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	vocabulary, err := loadSearchVocabulary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	search := textSearch{}
+	for _, term := range terms {
+		search = append(search, expandTerm(term, vocabulary))
+	}
+	return search, nil
+}
+
+// indexFilter matches profiles through the profile_text index. Every word is
+// plain letters and digits, so the $search string carries no phrases or negations.
+// $text treats the words as alternatives; pair it with patternFilter so every
+// term has to match.
+func (s textSearch) indexFilter() bson.M {
+	var words []string
+	for _, alternatives := range s {
+		words = append(words, alternatives...)
+	}
+	return bson.M{"$search": strings.Join(words, " ")}
+}
+
+// patternFilter matches every term at the start of a word of a searchable field.
+// It narrows $text matches down to profiles matching all terms, and stands in
+// for $text where it isn't allowed, such as inside $geoNear.
+func (s textSearch) patternFilter() []bson.M {
+	var conditions []bson.M
+	for _, alternatives := range s {
+		quoted := make([]string, len(alternatives))
+		for i, word := range alternatives {
+			quoted[i] = regexp.QuoteMeta(word)
+		}
+		pattern := primitive.Regex{Pattern: `\b(?:` + strings.Join(quoted, "|") + `)`, Options: "i"}
+
+		var fields []bson.M
+		for _, field := range profileTextFields {
+			fields = append(fields, bson.M{field: bson.M{"$regex": pattern}})
+		}
+		conditions = append(conditions, bson.M{"$or": fields})
+	}
+	return conditions
+}
+
+// relevanceExpr ranks text matches, lifting profiles with a running subscription
+func relevanceExpr() bson.M {
+	return bson.M{"$multiply": bson.A{
+		bson.M{"$meta": "textScore"},
+		bson.M{"$cond": bson.A{"$featured", featuredRelevanceBoost, 1}},
+	}}
+}
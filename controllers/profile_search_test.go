@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	many := make([]string, maxSearchTerms+3)
+	for i := range many {
+		many[i] = fmt.Sprintf("w%d", i)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"punctuation only", "!!! -- $$", nil},
+		{"lowercased", "Nairobi CBD", []string{"nairobi", "cbd"}},
+		{"operators dropped", `"massage" -spa $where .*`, []string{"massage", "spa", "where"}},
+		{"regex syntax dropped", "a|b (c)+", []string{"a", "b", "c"}},
+		{"duplicates dropped", "spa Spa SPA massage spa", []string{"spa", "massage"}},
+		{"digits kept", "studio 254", []string{"studio", "254"}},
+		{"non-latin letters kept", "café Mombasa", []string{"café", "mombasa"}},
+		{"capped", strings.Join(many, " "), many[:maxSearchTerms]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchTerms(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchTermsTruncatesLongInput(t *testing.T) {
+	text := strings.Repeat("a", maxSearchQueryLength) + " overflow"
+	if got := searchTerms(text); len(got) != 1 || got[0] != strings.Repeat("a", maxSearchQueryLength) {
+		t.Errorf("searchTerms kept %q past the length limit", got)
+	}
+
+	// Multi-byte letters count once and are never split
+	text = strings.Repeat("é", maxSearchQueryLength) + " overflow"
+	if got := searchTerms(text); len(got) != 1 || got[0] != strings.Repeat("é", maxSearchQueryLength) {
+		t.Errorf("searchTerms cut multi-byte input to %q", got)
+	}
+}
+
+func TestExpandTerm(t *testing.T) {
+	// Sorted, as loadSearchVocabulary returns it
+	vocabulary := []string{"kileleshwa", "kilimani", "kisumu", "massage", "masseuse", "mombasa", "nairobi", "spa", "sparkle", "spas"}
+
+	tests := []struct {
+		name string
+		term string
+		want []string
+	}{
+		{"short terms match whole words only", "sp", []string{"sp"}},
+		{"prefix", "spa", []string{"spa", "sparkle", "spas"}},
+		{"prefix of several places", "kil", []string{"kil", "kileleshwa", "kilimani"}},
+		{"one typo", "nairobu", []string{"nairobu", "nairobi"}},
+		{"two typos in a long word", "kilimaan", []string{"kilimaan", "kilimani"}},
+		{"too many typos", "nairxyz", []string{"nairxyz"}},
+		{"prefix and typo", "spar", []string{"spar", "sparkle", "spa", "spas"}},
+		{"missing letter", "massge", []string{"massge", "massage"}},
+		{"unknown word", "zzzz", []string{"zzzz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandTerm(tt.term, vocabulary); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandTerm(%q) = %q, want %q", tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTermIsCapped(t *testing.T) {
+	var vocabulary []string
+	for i := 0; i < maxTermExpansions*2; i++ {
+		vocabulary = append(vocabulary, fmt.Sprintf("spa%02d", i))
+	}
+	if got := expandTerm("spa", vocabulary); len(got) != maxTermExpansions {
+		t.Errorf("expandTerm gave %d words, want %d", len(got), maxTermExpansions)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{"", "", 2, 0},
+		{"spa", "spa", 2, 0},
+		{"spa", "spas", 2, 1},
+		{"spa", "sap", 2, 2},
+		{"nairobi", "nairobu", 2, 1},
+		{"kitten", "sitting", 3, 3},
+		{"café", "cafe", 2, 1},
+		{"abc", "abcdef", 2, 3},      // Length difference alone exceeds max
+		{"abcdef", "uvwxyz", 2, 3},   // Stops once every cell exceeds max
+		{"kitten", "sitting", 2, 3},  // Capped at max+1
+		{"mombasa", "mombasa", 0, 0}, // Exact matching
+		{"mombasa", "mombaza", 0, 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
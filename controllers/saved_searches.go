@@ -0,0 +1,522 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/notifications"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSavedSearches       = 20             // Per client
+	maxSavedSearchMatches  = 5000           // Matching profiles remembered per search
+	savedSearchDigestEvery = 24 * time.Hour // Between two daily digests
+	savedSearchPreviewSize = 5              // Profiles named in one notification
+)
+
+// savedSearchRequest creates or edits a saved search
+type savedSearchRequest struct {
+	Name      *string               `json:"name"`
+	Filters   *models.SearchFilters `json:"filters"`
+	Channel   *string               `json:"channel"`   // "in_app" (default), "email" or "telegram"
+	Frequency *string               `json:"frequency"` // "instant" (default) or "daily"
+	IsActive  *bool                 `json:"is_active"`
+}
+
+// savedSearchValues turns saved filters back into the query parameters of a search
+func savedSearchValues(filters models.SearchFilters) url.Values {
+	values := url.Values{}
+	set := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			values.Set(name, value)
+		}
+	}
+	set("q", filters.Text)
+	set("location", filters.Location)
+	set("gender", filters.Gender)
+	set("nationality", filters.Nationality)
+	set("orientation", filters.Orientation)
+	if filters.MinAge > 0 {
+		values.Set("min_age", strconv.Itoa(filters.MinAge))
+	}
+	if filters.MaxAge > 0 {
+		values.Set("max_age", strconv.Itoa(filters.MaxAge))
+	}
+	if filters.Verified {
+		values.Set("verified", "true")
+	}
+	for _, service := range filters.Services {
+		values.Add("service", service)
+	}
+	return values
+}
+
+// savedSearchQuery validates filters the way GET /search does
+func savedSearchQuery(filters models.SearchFilters) (ListingQuery, error) {
+	query, err := parseListingValues(savedSearchValues(filters), 10)
+	if err != nil {
+		return query, err
+	}
+	if !query.HasCriteria() {
+		return query, fmt.Errorf("at least one filter (q, location, service, gender, min_age, max_age, nationality, orientation, verified) is required")
+	}
+	return query, nil
+}
+
+// validateSavedSearchDelivery checks the channel and frequency of a saved search
+func validateSavedSearchDelivery(channel, frequency string) error {
+	if _, ok := notifications.Lookup(channel); !ok {
+		return fmt.Errorf("channel must be %s, %s or %s", notifications.ChannelInApp, notifications.ChannelEmail, notifications.ChannelTelegram)
+	}
+	if frequency != models.SearchInstant && frequency != models.SearchDaily {
+		return fmt.Errorf("frequency must be %s or %s", models.SearchInstant, models.SearchDaily)
+	}
+	return nil
+}
+
+// matchingProfileIDs lists the visible profiles matching q, newest first
+func matchingProfileIDs(ctx context.Context, q ListingQuery, now time.Time) ([]primitive.ObjectID, error) {
+	if err := q.resolve(ctx); err != nil {
+		return nil, err
+	}
+	cursor, err := database.UserCollection.Find(ctx, q.Filter(now), options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(maxSavedSearchMatches))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+// findSavedSearch loads one of the signed-in client's saved searches
+func findSavedSearch(ctx context.Context, c *gin.Context) (*models.SavedSearch, bool) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return nil, false
+	}
+	searchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid saved search ID"})
+		return nil, false
+	}
+
+	var search models.SavedSearch
+	if err := database.SavedSearchCollection.FindOne(ctx, bson.M{"_id": searchID, "user_id": userObjID}).Decode(&search); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Saved search not found"})
+		return nil, false
+	}
+	return &search, true
+}
+
+// CreateSavedSearch - POST /auth/client/saved-searches {"name", "filters", "channel", "frequency"}
+// filters take the parameters of GET /search. Profiles matching already are not
+// reported; only those that start matching later are.
+func CreateSavedSearch(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Filters == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "filters are required"})
+		return
+	}
+	query, err := savedSearchQuery(*req.Filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	search := models.SavedSearch{
+		ID:           primitive.NewObjectID(),
+		UserID:       userObjID,
+		Name:         "Saved search",
+		Filters:      *req.Filters,
+		Channel:      notifications.ChannelInApp,
+		Frequency:    models.SearchInstant,
+		IsActive:     req.IsActive == nil || *req.IsActive,
+		LastDigestAt: now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		search.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Channel != nil {
+		search.Channel = *req.Channel
+	}
+	if req.Frequency != nil {
+		search.Frequency = *req.Frequency
+	}
+	if err := validateSavedSearchDelivery(search.Channel, search.Frequency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if count, _ := database.SavedSearchCollection.CountDocuments(ctx, bson.M{"user_id": userObjID}); count >= maxSavedSearches {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": fmt.Sprintf("You can save up to %d searches", maxSavedSearches)})
+		return
+	}
+
+	if search.SeenIDs, err = matchingProfileIDs(ctx, query, now); err != nil {
+		listingError(c, err)
+		return
+	}
+	if _, err := database.SavedSearchCollection.InsertOne(ctx, search); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to save search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":       true,
+		"message":       "Search saved",
+		"data":          search,
+		"current_count": len(search.SeenIDs),
+	})
+}
+
+// GetSavedSearches - GET /auth/client/saved-searches
+func GetSavedSearches(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.SavedSearchCollection.Find(ctx, bson.M{"user_id": userObjID},
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetProjection(bson.M{"seen_ids": 0, "pending_ids": 0}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch saved searches"})
+		return
+	}
+	searches := []models.SavedSearch{}
+	if err := cursor.All(ctx, &searches); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to read saved searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(searches),
+		"data":    searches,
+	})
+}
+
+// UpdateSavedSearch - PUT /auth/client/saved-searches/:id {"name", "filters", "channel", "frequency", "is_active"}
+// New filters start over: profiles matching them now are not reported.
+func UpdateSavedSearch(c *gin.Context) {
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request data"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	search, ok := findSavedSearch(ctx, c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	update := bson.M{"updated_at": now}
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		update["name"] = strings.TrimSpace(*req.Name)
+	}
+	channel, frequency := search.Channel, search.Frequency
+	if req.Channel != nil {
+		channel = *req.Channel
+		update["channel"] = channel
+	}
+	if req.Frequency != nil {
+		frequency = *req.Frequency
+		update["frequency"] = frequency
+		if frequency == models.SearchInstant {
+			update["pending_ids"] = []primitive.ObjectID{}
+		}
+	}
+	if err := validateSavedSearchDelivery(channel, frequency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	if req.IsActive != nil {
+		update["is_active"] = *req.IsActive
+	}
+	if req.Filters != nil {
+		query, err := savedSearchQuery(*req.Filters)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+			return
+		}
+		seen, err := matchingProfileIDs(ctx, query, now)
+		if err != nil {
+			listingError(c, err)
+			return
+		}
+		update["filters"] = *req.Filters
+		update["seen_ids"] = seen
+		update["pending_ids"] = []primitive.ObjectID{}
+	}
+
+	var updated models.SavedSearch
+	err := database.SavedSearchCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": search.ID},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to update saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Saved search updated",
+		"data":    updated,
+	})
+}
+
+// DeleteSavedSearch - DELETE /auth/client/saved-searches/:id
+func DeleteSavedSearch(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	search, ok := findSavedSearch(ctx, c)
+	if !ok {
+		return
+	}
+	if _, err := database.SavedSearchCollection.DeleteOne(ctx, bson.M{"_id": search.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to delete saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Saved search deleted",
+	})
+}
+
+// GetSavedSearchResults - GET /auth/client/saved-searches/:id/results?sort=&page=&limit=&cursor=
+// Runs the saved search like GET /search.
+func GetSavedSearchResults(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	search, ok := findSavedSearch(ctx, c)
+	if !ok {
+		return
+	}
+
+	values := savedSearchValues(search.Filters)
+	for _, name := range []string{"sort", "page", "limit", "cursor"} {
+		if value := c.Query(name); value != "" {
+			values.Set(name, value)
+		}
+	}
+	query, err := parseListingValues(values, 10)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	page, err := RunListing(ctx, query)
+	if err != nil {
+		listingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"data":         page.Users,
+		"pagination":   listingPagination(query, page),
+		"next_cursor":  page.NextCursor,
+		"has_more":     page.HasMore,
+		"saved_search": search,
+	})
+}
+
+// MatchSavedSearches looks for profiles that started matching each active saved
+// search since its last run and notifies the clients, at once or in a daily digest
+func MatchSavedSearches(ctx context.Context) error {
+	cursor, err := database.SavedSearchCollection.Find(ctx, bson.M{"is_active": true})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	notified := 0
+	for cursor.Next(ctx) {
+		var search models.SavedSearch
+		if err := cursor.Decode(&search); err != nil {
+			continue
+		}
+		sent, err := matchSavedSearch(ctx, search, time.Now())
+		if err != nil {
+			fmt.Printf("⚠️ Saved search %s failed: %v\n", search.ID.Hex(), err)
+			continue
+		}
+		if sent {
+			notified++
+		}
+	}
+	if notified > 0 {
+		fmt.Printf("🔔 Notified %d saved searches of new matches\n", notified)
+	}
+	return cursor.Err()
+}
+
+// matchSavedSearch runs one saved search and reports whether a notification went out
+func matchSavedSearch(ctx context.Context, search models.SavedSearch, now time.Time) (bool, error) {
+	query, err := savedSearchQuery(search.Filters)
+	if err != nil {
+		return false, err
+	}
+	current, err := matchingProfileIDs(ctx, query, now)
+	if err != nil {
+		return false, err
+	}
+
+	seen := map[primitive.ObjectID]bool{}
+	for _, id := range search.SeenIDs {
+		seen[id] = true
+	}
+	matching := map[primitive.ObjectID]bool{}
+	var fresh []primitive.ObjectID
+	for _, id := range current {
+		matching[id] = true
+		if !seen[id] {
+			fresh = append(fresh, id)
+		}
+	}
+
+	update := bson.M{"seen_ids": current}
+	if len(fresh) > 0 {
+		update["last_matched_at"] = now
+	}
+
+	// New matches stay pending until a notification about them went out, so a
+	// failed delivery is retried at the next run. Daily searches collect them
+	// until the digest is due. Profiles that stopped matching in the meantime
+	// are left out.
+	pending := []primitive.ObjectID{}
+	queued := map[primitive.ObjectID]bool{}
+	for _, id := range append(fresh, search.PendingIDs...) {
+		if matching[id] && !queued[id] {
+			queued[id] = true
+			pending = append(pending, id)
+		}
+	}
+
+	sent := false
+	var deliveryErr error
+	daily := search.Frequency == models.SearchDaily
+	if !daily || now.Sub(search.LastDigestAt) >= savedSearchDigestEvery {
+		if len(pending) > 0 {
+			deliveryErr = notifySavedSearchMatches(ctx, search, pending)
+			sent = deliveryErr == nil
+		}
+		if deliveryErr == nil {
+			pending = []primitive.ObjectID{}
+			if daily {
+				update["last_digest_at"] = now
+			}
+		}
+	}
+	update["pending_ids"] = pending
+
+	// An edit while the search ran replaced what it was compared against
+	if _, err := database.SavedSearchCollection.UpdateOne(ctx,
+		bson.M{"_id": search.ID, "updated_at": search.UpdatedAt},
+		bson.M{"$set": update},
+	); err != nil {
+		return sent, err
+	}
+	return sent, deliveryErr
+}
+
+// notifySavedSearchMatches tells the client which profiles matched their search,
+// naming the first few
+func notifySavedSearchMatches(ctx context.Context, search models.SavedSearch, ids []primitive.ObjectID) error {
+	var client models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": search.UserID}).Decode(&client); err != nil {
+		return fmt.Errorf("client not found: %w", err)
+	}
+
+	preview := ids[:min(len(ids), savedSearchPreviewSize)]
+	cursor, err := database.UserCollection.Find(ctx, bson.M{"_id": bson.M{"$in": preview}},
+		options.Find().SetProjection(bson.M{"first_name": 1, "last_name": 1, "location": 1}))
+	if err != nil {
+		return err
+	}
+	var profiles []models.User
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("New match for \"%s\"", search.Name)
+	if len(ids) > 1 {
+		title = fmt.Sprintf("%d new matches for \"%s\"", len(ids), search.Name)
+	}
+	var lines []string
+	for _, profile := range profiles {
+		name := strings.TrimSpace(profile.FirstName + " " + profile.LastName)
+		if profile.Location != "" {
+			name += ", " + profile.Location
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s/provider/%s", name, siteURL(), profile.ID.Hex()))
+	}
+	if more := len(ids) - len(profiles); more > 0 {
+		lines = append(lines, fmt.Sprintf("…and %d more", more))
+	}
+
+	profileIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		profileIDs = append(profileIDs, id.Hex())
+	}
+
+	return notifications.Deliver(ctx, search.Channel, client, notifications.Message{
+		Type:  notifications.TypeSavedSearchMatches,
+		Title: title,
+		Body:  strings.Join(lines, "\n"),
+		Data: map[string]interface{}{
+			"saved_search_id": search.ID.Hex(),
+			"profile_ids":     profileIDs,
+		},
+	})
+}
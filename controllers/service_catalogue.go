@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// serviceCatalogue maps every lookup key (slug, name, alias) to its service
+type serviceCatalogue map[string]models.Service
+
+// loadServiceCatalogue reads the catalogue, optionally only the services providers may pick
+func loadServiceCatalogue(ctx context.Context, activeOnly bool) (serviceCatalogue, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["is_active"] = true
+	}
+
+	cursor, err := database.ServiceCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var services []models.Service
+	if err := cursor.All(ctx, &services); err != nil {
+		return nil, err
+	}
+
+	catalogue := serviceCatalogue{}
+	for _, service := range services {
+		for _, key := range service.Keys {
+			catalogue[key] = service
+		}
+	}
+	return catalogue, nil
+}
+
+// lookup finds the service a user typed, ignoring case and surrounding spaces
+func (catalogue serviceCatalogue) lookup(value string) (models.Service, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if service, ok := catalogue[value]; ok {
+		return service, true
+	}
+	service, ok := catalogue[models.Slugify(value)]
+	return service, ok
+}
+
+// normalize maps values onto catalogue names, dropping duplicates. Values that
+// match no service are returned separately.
+func (catalogue serviceCatalogue) normalize(values []string) (names []string, unknown []string) {
+	names = []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		service, ok := catalogue.lookup(value)
+		if !ok {
+			unknown = append(unknown, value)
+			continue
+		}
+		if !seen[service.Name] {
+			seen[service.Name] = true
+			names = append(names, service.Name)
+		}
+	}
+	return names, unknown
+}
+
+// NormalizeServices validates the services a provider picked against the active
+// catalogue and returns their canonical names
+func NormalizeServices(ctx context.Context, values []string) ([]string, error) {
+	catalogue, err := loadServiceCatalogue(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	names, unknown := catalogue.normalize(values)
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown services: %s. Choose from /services", strings.Join(unknown, ", "))
+	}
+	return names, nil
+}
+
+// GetServiceCatalogue - GET /services
+// Lists the services providers can offer, grouped by category.
+func GetServiceCatalogue(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.ServiceCollection.Find(ctx,
+		bson.M{"is_active": true},
+		options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to fetch services"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	services := []models.Service{}
+	if err := cursor.All(ctx, &services); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to read services"})
+		return
+	}
+
+	categories := []gin.H{}
+	index := map[string]int{}
+	for _, service := range services {
+		i, ok := index[service.Category]
+		if !ok {
+			i = len(categories)
+			index[service.Category] = i
+			categories = append(categories, gin.H{"category": service.Category, "services": []models.Service{}})
+		}
+		categories[i]["services"] = append(categories[i]["services"].([]models.Service), service)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"count":      len(services),
+		"data":       services,
+		"categories": categories,
+	})
+}
+
+// MigrateUserServices maps free-text services (including ones saved as a comma
+// separated string) onto the catalogue. Values that match nothing are moved to
+// legacy_services for admins to review.
+func MigrateUserServices() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	catalogue, err := loadServiceCatalogue(ctx, false)
+	if err != nil || len(catalogue) == 0 {
+		return
+	}
+
+	var names []string
+	for _, service := range catalogue {
+		names = append(names, service.Name)
+	}
+
+	cursor, err := database.UserCollection.Find(ctx,
+		bson.M{"$or": []bson.M{
+			{"services": bson.M{"$type": "string"}},
+			{"services": bson.M{"$elemMatch": bson.M{"$nin": names}}},
+		}},
+		options.Find().SetProjection(bson.M{"services": 1}),
+	)
+	if err != nil {
+		fmt.Printf("⚠️ Could not load users for services migration: %v\n", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var user bson.M
+		if err := cursor.Decode(&user); err != nil {
+			continue
+		}
+		userObjID, _ := user["_id"].(primitive.ObjectID)
+
+		mapped, unknown := catalogue.normalize(servicesList(user["services"]))
+		update := bson.M{"$set": bson.M{"services": mapped}}
+		if len(unknown) > 0 {
+			update["$addToSet"] = bson.M{"legacy_services": bson.M{"$each": unknown}}
+		}
+		if _, err := database.UserCollection.UpdateOne(ctx, bson.M{"_id": userObjID}, update); err != nil {
+			fmt.Printf("⚠️ Could not migrate services of user %s: %v\n", userObjID.Hex(), err)
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		fmt.Printf("✅ Mapped the services of %d profiles onto the catalogue\n", migrated)
+	}
+}
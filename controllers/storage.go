@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServeSignedObject - GET /storage/signed/:bucket/*key?expires=&signature=
+// Serves files from the local object store for signed URLs. Remote backends
+// hand out their own signed URLs, so this is only used in development.
+func ServeSignedObject(c *gin.Context) {
+	path, err := services.LocalSignedObjectPath(
+		c.Param("bucket"),
+		strings.TrimPrefix(c.Param("key"), "/"),
+		c.Query("expires"),
+		c.Query("signature"),
+	)
+	if errors.Is(err, services.ErrNoSigningKey) {
+		fmt.Printf("❌ Refusing signed object: %v\n", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Signed URLs are not configured"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.File(path)
+}
@@ -17,12 +17,13 @@ import (
 func GetNextUnadvertisedCreator(c *gin.Context) {
 	var creator models.User
 
-	// Find first creator where advertised is false and is_active is true
+	// Find first creator where advertised is false, is_active is true and the age is confirmed
 	err := database.UserCollection.FindOne(
 		context.TODO(),
 		bson.M{
-			"advertised": false,
-			"is_active":  true,
+			"advertised":      false,
+			"is_active":       true,
+			"age_verified_at": bson.M{"$ne": nil},
 		},
 	).Decode(&creator)
 
@@ -34,15 +35,15 @@ func GetNextUnadvertisedCreator(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"id":          creator.ID.Hex(),
-			"full_name":   creator.FirstName + " " + creator.LastName,
-			"image_url":   creator.ImageUrl,
-			"bio":         creator.Nationality, // or use a bio field if you have one
-			"services":    creator.Services,
-			"location":    creator.Location,
-			"phone_no":    creator.PhoneNo,
-			"age":         creator.Age,
-			"gender":      creator.Gender,
+			"id":        creator.ID.Hex(),
+			"full_name": creator.FirstName + " " + creator.LastName,
+			"image_url": creator.ImageUrl,
+			"bio":       creator.Nationality, // or use a bio field if you have one
+			"services":  creator.Services,
+			"location":  creator.Location,
+			"phone_no":  creator.PhoneNo,
+			"age":       creator.Age,
+			"gender":    creator.Gender,
 		},
 	})
 }
@@ -73,4 +74,4 @@ func MarkCreatorAdvertised(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Creator marked as advertised"})
-}
\ No newline at end of file
+}
@@ -0,0 +1,477 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resumable uploads follow the tus 1.0 core protocol with the creation and
+// termination extensions: POST creates an upload, HEAD reports how many bytes
+// arrived and PATCH appends bytes at that offset.
+const (
+	tusVersion       = "1.0.0"
+	uploadSessionTTL = 24 * time.Hour
+	maxUploadsPerDay = 30
+	maxUploadChunks  = 1000
+)
+
+// setTusHeaders adds the headers every tus response carries
+func setTusHeaders(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", "creation,termination")
+	c.Header("Tus-Max-Size", strconv.Itoa(maxProfileImageBytes))
+	c.Header("Cache-Control", "no-store")
+}
+
+// parseUploadMetadata decodes the Upload-Metadata header ("key base64value,key2 base64value2")
+func parseUploadMetadata(header string) map[string]string {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), " ", 2)
+		if parts[0] == "" {
+			continue
+		}
+		value := ""
+		if len(parts) == 2 {
+			if decoded, err := base64.StdEncoding.DecodeString(parts[1]); err == nil {
+				value = string(decoded)
+			}
+		}
+		metadata[parts[0]] = value
+	}
+	return metadata
+}
+
+// uploadChunkKey names a new chunk of session starting at offset. The random
+// segment keeps a request that loses the race for an offset from deleting the
+// chunk of the one that won it.
+func uploadChunkKey(session *models.UploadSession, offset int64) (string, error) {
+	segment, err := services.RandomKeySegment()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s/%s/%012d-%s", services.UploadChunkPrefix, session.UserID.Hex(), session.ID.Hex(), offset, segment), nil
+}
+
+// loadUploadSession finds an upload of the current user, answering the request itself when it can't
+func loadUploadSession(ctx context.Context, c *gin.Context) (*models.UploadSession, bool) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return nil, false
+	}
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+		return nil, false
+	}
+
+	var session models.UploadSession
+	err = database.UploadSessionCollection.FindOne(ctx, bson.M{"_id": sessionID, "user_id": userObjID}).Decode(&session)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+		return nil, false
+	}
+
+	if session.Status == models.UploadStatusUploading && time.Now().After(session.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "Upload has expired, please start again"})
+		return nil, false
+	}
+	return &session, true
+}
+
+// deleteUploadChunks removes the stored chunks of an upload
+func deleteUploadChunks(ctx context.Context, chunks []string) {
+	if len(chunks) == 0 {
+		return
+	}
+	store, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		return
+	}
+	for _, key := range chunks {
+		if err := store.Delete(ctx, key); err != nil {
+			fmt.Printf("⚠️ Could not delete upload chunk %s: %v\n", key, err)
+		}
+	}
+}
+
+// CreateUpload - POST /auth/uploads (headers: Upload-Length, Upload-Metadata)
+func CreateUpload(c *gin.Context) {
+	setTusHeaders(c)
+
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	size, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Length header is required"})
+		return
+	}
+	if size > maxProfileImageBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Photos must be %dMB or smaller", maxProfileImageBytes>>20)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	photos, err := loadUserPhotos(ctx, userObjID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	// Quota: unfinished uploads count against the photo limit, and uploads per day are capped
+	now := time.Now()
+	active, err := database.UploadSessionCollection.CountDocuments(ctx, bson.M{
+		"user_id":    userObjID,
+		"status":     bson.M{"$in": bson.A{models.UploadStatusUploading, models.UploadStatusProcessing}},
+		"expires_at": bson.M{"$gt": now},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check upload quota"})
+		return
+	}
+	if len(photos.Images)+int(active) >= maxProfileImages {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": fmt.Sprintf("Maximum %d photos allowed. You have %d photos and %d uploads in progress",
+				maxProfileImages, len(photos.Images), active),
+		})
+		return
+	}
+
+	today, err := database.UploadSessionCollection.CountDocuments(ctx, bson.M{
+		"user_id":    userObjID,
+		"created_at": bson.M{"$gt": now.Add(-24 * time.Hour)},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check upload quota"})
+		return
+	}
+	if today >= maxUploadsPerDay {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily upload limit reached, please try again tomorrow"})
+		return
+	}
+
+	session := models.UploadSession{
+		ID:        primitive.NewObjectID(),
+		UserID:    userObjID,
+		Filename:  parseUploadMetadata(c.GetHeader("Upload-Metadata"))["filename"],
+		Size:      size,
+		Chunks:    []string{},
+		Status:    models.UploadStatusUploading,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(uploadSessionTTL),
+	}
+	if _, err := database.UploadSessionCollection.InsertOne(ctx, session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload"})
+		return
+	}
+
+	c.Header("Location", "/auth/uploads/"+session.ID.Hex())
+	c.Header("Upload-Offset", "0")
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"upload":  session,
+	})
+}
+
+// GetUploadOffset - HEAD /auth/uploads/:id
+func GetUploadOffset(c *gin.Context) {
+	setTusHeaders(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, ok := loadUploadSession(ctx, c)
+	if !ok {
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Size, 10))
+	c.Status(http.StatusOK)
+}
+
+// GetUpload - GET /auth/uploads/:id
+func GetUpload(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, ok := loadUploadSession(ctx, c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"upload":  session,
+	})
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// checkUploadChunk reports whether a chunk of contentLength bytes (-1 when
+// unknown) may be appended to session at offset. The int is the HTTP status to
+// report when it may not.
+func checkUploadChunk(session *models.UploadSession, offset, contentLength int64) (int, error) {
+	if session.Status != models.UploadStatusUploading {
+		return http.StatusConflict, errors.New("Upload is already " + session.Status)
+	}
+	if offset != session.Offset {
+		return http.StatusConflict, errors.New("Upload-Offset does not match the received bytes")
+	}
+	if len(session.Chunks) >= maxUploadChunks {
+		return http.StatusBadRequest, errors.New("Too many chunks, please send larger chunks")
+	}
+	if contentLength > session.Size-session.Offset {
+		return http.StatusRequestEntityTooLarge, errors.New("Chunk is larger than the rest of the upload")
+	}
+	return http.StatusOK, nil
+}
+
+// PatchUpload - PATCH /auth/uploads/:id (headers: Upload-Offset; body: application/offset+octet-stream)
+func PatchUpload(c *gin.Context) {
+	setTusHeaders(c)
+
+	if c.ContentType() != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/offset+octet-stream"})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset header is required"})
+		return
+	}
+
+	// Slow mobile links get the whole request timeout for one chunk
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	session, ok := loadUploadSession(ctx, c)
+	if !ok {
+		return
+	}
+	if status, err := checkUploadChunk(session, offset, c.Request.ContentLength); err != nil {
+		if status == http.StatusConflict {
+			c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	remaining := session.Size - session.Offset
+
+	store, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Storage configuration missing"})
+		return
+	}
+
+	key, err := uploadChunkKey(session, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store chunk, please resume the upload"})
+		return
+	}
+
+	// Stream the body straight into storage
+	body := &countingReader{reader: http.MaxBytesReader(c.Writer, c.Request.Body, remaining)}
+
+	if err := store.Put(ctx, key, body, c.Request.ContentLength, "application/octet-stream"); err != nil {
+		store.Delete(ctx, key)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Chunk is larger than the rest of the upload"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store chunk, please resume the upload"})
+		return
+	}
+	if body.n == 0 {
+		store.Delete(ctx, key)
+		c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	// Only advance if nobody else appended in the meantime
+	now := time.Now()
+	newOffset := offset + body.n
+	result, err := database.UploadSessionCollection.UpdateOne(ctx,
+		bson.M{"_id": session.ID, "offset": offset, "status": models.UploadStatusUploading},
+		bson.M{
+			"$set": bson.M{
+				"offset":     newOffset,
+				"updated_at": now,
+				"expires_at": now.Add(uploadSessionTTL),
+			},
+			"$push": bson.M{"chunks": key},
+		},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		store.Delete(ctx, key)
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload changed while this chunk was sent, check the offset and resume"})
+		return
+	}
+	session.Offset = newOffset
+	session.Chunks = append(session.Chunks, key)
+
+	c.Header("Upload-Offset", strconv.FormatInt(newOffset, 10))
+	if newOffset < session.Size {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	completeUpload(ctx, c, store, session)
+}
+
+// completeUpload turns a fully received upload into a profile photo
+func completeUpload(ctx context.Context, c *gin.Context, store services.ObjectStore, session *models.UploadSession) {
+	// Claim the upload so a retried final PATCH can't process it twice
+	result, err := database.UploadSessionCollection.UpdateOne(ctx,
+		bson.M{"_id": session.ID, "status": models.UploadStatusUploading},
+		bson.M{"$set": bson.M{"status": models.UploadStatusProcessing, "updated_at": time.Now()}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload is already being processed"})
+		return
+	}
+
+	image, photos, status, err := attachUploadedPhoto(ctx, store, session)
+	deleteUploadChunks(ctx, session.Chunks)
+
+	if err != nil {
+		database.UploadSessionCollection.UpdateOne(ctx,
+			bson.M{"_id": session.ID},
+			bson.M{"$set": bson.M{
+				"status":     models.UploadStatusFailed,
+				"error":      err.Error(),
+				"chunks":     []string{},
+				"updated_at": time.Now(),
+			}},
+		)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	database.UploadSessionCollection.UpdateOne(ctx,
+		bson.M{"_id": session.ID},
+		bson.M{"$set": bson.M{
+			"status":     models.UploadStatusComplete,
+			"image_id":   image.ID,
+			"chunks":     []string{},
+			"updated_at": time.Now(),
+		}},
+	)
+
+	response := photos.response()
+	response["message"] = "Image uploaded successfully and is awaiting review"
+	response["uploaded"] = []models.ProfileImage{image}
+	c.JSON(http.StatusOK, response)
+}
+
+// attachUploadedPhoto joins the chunks of session, processes the photo and adds
+// it to the user's photos. The int is the HTTP status to report on failure.
+func attachUploadedPhoto(ctx context.Context, store services.ObjectStore, session *models.UploadSession) (models.ProfileImage, *userPhotos, int, error) {
+	var data bytes.Buffer
+	data.Grow(int(session.Size))
+	for _, key := range session.Chunks {
+		chunk, err := store.Get(ctx, key)
+		if err != nil {
+			return models.ProfileImage{}, nil, http.StatusInternalServerError, fmt.Errorf("failed to read uploaded data: %w", err)
+		}
+		_, err = io.Copy(&data, io.LimitReader(chunk, session.Size-int64(data.Len())))
+		chunk.Close()
+		if err != nil {
+			return models.ProfileImage{}, nil, http.StatusInternalServerError, fmt.Errorf("failed to read uploaded data: %w", err)
+		}
+	}
+
+	photos, err := loadUserPhotos(ctx, session.UserID)
+	if err != nil {
+		return models.ProfileImage{}, nil, http.StatusInternalServerError, errors.New("failed to fetch user")
+	}
+	if len(photos.Images) >= maxProfileImages {
+		return models.ProfileImage{}, nil, http.StatusForbidden, fmt.Errorf("maximum %d photos allowed", maxProfileImages)
+	}
+
+	uploader, err := newPhotoUploader(ctx, session.UserID)
+	if err != nil {
+		return models.ProfileImage{}, nil, http.StatusInternalServerError, err
+	}
+
+	image, err := uploader.process(ctx, data.Bytes())
+	if err != nil {
+		uploader.discard(ctx, []models.ProfileImage{image})
+		if errors.Is(err, errInvalidProfileImage) {
+			return image, nil, http.StatusUnprocessableEntity, err
+		}
+		return image, nil, http.StatusInternalServerError, fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	if err := uploader.attach(ctx, photos, []models.ProfileImage{image}); err != nil {
+		if errors.Is(err, errTooManyPhotos) {
+			return image, nil, http.StatusForbidden, err
+		}
+		return image, nil, http.StatusInternalServerError, fmt.Errorf("failed to update user: %w", err)
+	}
+	return image, photos, http.StatusOK, nil
+}
+
+// DeleteUpload - DELETE /auth/uploads/:id (tus termination)
+func DeleteUpload(c *gin.Context) {
+	setTusHeaders(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	session, ok := loadUploadSession(ctx, c)
+	if !ok {
+		return
+	}
+	if session.Status == models.UploadStatusProcessing {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload is being processed"})
+		return
+	}
+
+	_, err := database.UploadSessionCollection.DeleteOne(ctx, bson.M{"_id": session.ID})
+	if err != nil && err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel upload"})
+		return
+	}
+	deleteUploadChunks(ctx, session.Chunks)
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"escort/models"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	encode := func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		want   map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"one pair", "filename " + encode("photo.jpg"), map[string]string{"filename": "photo.jpg"}},
+		{
+			"several pairs",
+			"filename " + encode("beach day.png") + ",filetype " + encode("image/png"),
+			map[string]string{"filename": "beach day.png", "filetype": "image/png"},
+		},
+		{"spaces around pairs", " filename " + encode("a.jpg") + " , filetype " + encode("image/jpeg"), map[string]string{"filename": "a.jpg", "filetype": "image/jpeg"}},
+		{"key without value", "is_confidential", map[string]string{"is_confidential": ""}},
+		{"invalid base64", "filename ***", map[string]string{"filename": ""}},
+		{"empty pairs skipped", ",,filename " + encode("b.jpg") + ",", map[string]string{"filename": "b.jpg"}},
+		{"non-ascii value", "filename " + encode("café.jpg"), map[string]string{"filename": "café.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseUploadMetadata(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseUploadMetadata(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadChunkKey(t *testing.T) {
+	session := &models.UploadSession{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+
+	first, err := uploadChunkKey(session, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := uploadChunkKey(session, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two requests racing for one offset must not write to the same object
+	if first == second {
+		t.Errorf("both chunks at offset 1024 got the key %q", first)
+	}
+
+	// The cleanup job reads uploads/<userID>/<sessionID>/<chunk>
+	prefix := services.UploadChunkPrefix + session.UserID.Hex() + "/" + session.ID.Hex() + "/000000001024-"
+	for _, key := range []string{first, second} {
+		if !strings.HasPrefix(key, prefix) {
+			t.Errorf("chunk key %q does not start with %q", key, prefix)
+		}
+		if parts := strings.Split(strings.TrimPrefix(key, services.UploadChunkPrefix), "/"); len(parts) != 3 {
+			t.Errorf("chunk key %q has %d segments, want 3", key, len(parts))
+		}
+	}
+}
+
+func TestPatchUploadRejectsBadRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		offset      string
+		want        int
+	}{
+		{"wrong content type", "application/octet-stream", "0", http.StatusUnsupportedMediaType},
+		{"missing content type", "", "0", http.StatusUnsupportedMediaType},
+		{"missing offset", "application/offset+octet-stream", "", http.StatusBadRequest},
+		{"negative offset", "application/offset+octet-stream", "-1", http.StatusBadRequest},
+		{"offset not a number", "application/offset+octet-stream", "12b", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest("PATCH", "/auth/uploads/"+primitive.NewObjectID().Hex(), strings.NewReader("data"))
+			if tt.contentType != "" {
+				c.Request.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.offset != "" {
+				c.Request.Header.Set("Upload-Offset", tt.offset)
+			}
+
+			PatchUpload(c)
+
+			if recorder.Code != tt.want {
+				t.Errorf("status %d, want %d", recorder.Code, tt.want)
+			}
+			if got := recorder.Header().Get("Tus-Resumable"); got != tusVersion {
+				t.Errorf("Tus-Resumable header %q, want %q", got, tusVersion)
+			}
+		})
+	}
+}
+
+func TestCheckUploadChunk(t *testing.T) {
+	uploading := func(size, offset int64, chunks int) *models.UploadSession {
+		return &models.UploadSession{
+			Status: models.UploadStatusUploading,
+			Size:   size,
+			Offset: offset,
+			Chunks: make([]string, chunks),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		session       *models.UploadSession
+		offset        int64
+		contentLength int64
+		want          int
+	}{
+		{"first chunk", uploading(1000, 0, 0), 0, 400, http.StatusOK},
+		{"next chunk", uploading(1000, 400, 1), 400, 400, http.StatusOK},
+		{"last chunk fills the upload", uploading(1000, 800, 2), 800, 200, http.StatusOK},
+		{"unknown length", uploading(1000, 0, 0), 0, -1, http.StatusOK},
+		{"offset behind", uploading(1000, 400, 1), 0, 400, http.StatusConflict},
+		{"offset ahead", uploading(1000, 400, 1), 800, 200, http.StatusConflict},
+		{"already processing", &models.UploadSession{Status: models.UploadStatusProcessing, Size: 1000, Offset: 1000}, 1000, 0, http.StatusConflict},
+		{"already complete", &models.UploadSession{Status: models.UploadStatusComplete, Size: 1000, Offset: 1000}, 1000, 0, http.StatusConflict},
+		{"too many chunks", uploading(1000, 500, maxUploadChunks), 500, 10, http.StatusBadRequest},
+		{"past the end", uploading(1000, 800, 2), 800, 201, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkUploadChunk(tt.session, tt.offset, tt.contentLength)
+			if got != tt.want {
+				t.Errorf("checkUploadChunk = %d (%v), want %d", got, err, tt.want)
+			}
+			if (err == nil) != (tt.want == http.StatusOK) {
+				t.Errorf("checkUploadChunk error %v with status %d", err, got)
+			}
+		})
+	}
+}
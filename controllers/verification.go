@@ -0,0 +1,401 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	verificationCodeLength  = 6
+	verificationCodeTTL     = 30 * time.Minute
+	maxVerificationBytes    = 10 << 20
+	verificationPhotoSize   = 2000
+	verificationCodeLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No 0/O or 1/I look-alikes
+)
+
+// newVerificationCode returns a random code that is easy to read off a photo
+func newVerificationCode() (string, error) {
+	code := make([]byte, verificationCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(verificationCodeLetters))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = verificationCodeLetters[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// latestVerification returns the user's most recent verification of a type, or nil
+func latestVerification(ctx context.Context, userObjID primitive.ObjectID, verificationType string) (*models.Verification, error) {
+	var verification models.Verification
+	err := database.VerificationCollection.FindOne(ctx,
+		bson.M{"user_id": userObjID, "type": verificationType},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	).Decode(&verification)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// StartVerification - POST /auth/verification/start
+// Issues the code the provider must hold up in their selfie.
+func StartVerification(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.VerifiedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your profile is already verified"})
+		return
+	}
+
+	latest, err := latestVerification(ctx, userObjID, models.VerificationTypeSelfie)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load verification"})
+		return
+	}
+	if latest != nil && latest.Status == models.VerificationPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your selfie is already awaiting review", "verification": latest})
+		return
+	}
+
+	// Keep handing out the same code until it expires
+	if latest != nil && latest.Status == models.VerificationAwaitingSubmission && time.Now().Before(latest.CodeExpiresAt) {
+		c.JSON(http.StatusOK, gin.H{
+			"success":      true,
+			"verification": latest,
+			"instructions": verificationInstructions(latest.Code),
+		})
+		return
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create verification code"})
+		return
+	}
+
+	now := time.Now()
+	verification := models.Verification{
+		ID:            primitive.NewObjectID(),
+		UserID:        userObjID,
+		Type:          models.VerificationTypeSelfie,
+		Code:          code,
+		CodeExpiresAt: now.Add(verificationCodeTTL),
+		Status:        models.VerificationAwaitingSubmission,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if _, err := database.VerificationCollection.InsertOne(ctx, verification); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start verification"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"verification": verification,
+		"instructions": verificationInstructions(code),
+	})
+}
+
+// verificationInstructions tells the provider how to take the selfie
+func verificationInstructions(code string) string {
+	return fmt.Sprintf("Take a clear selfie holding a paper with the code %s written on it. Your face and the code must be fully visible. The code expires in %d minutes.",
+		code, int(verificationCodeTTL.Minutes()))
+}
+
+// SubmitVerificationSelfie - POST /auth/verification/selfie (multipart: selfie)
+func SubmitVerificationSelfie(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxVerificationBytes+1<<20)
+
+	fileHeader, err := c.FormFile("selfie")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Selfie file is required"})
+		return
+	}
+	if fileHeader.Size > maxVerificationBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Selfie must be %dMB or smaller", maxVerificationBytes>>20)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	verification, err := latestVerification(ctx, userObjID, models.VerificationTypeSelfie)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load verification"})
+		return
+	}
+	if verification == nil || verification.Status != models.VerificationAwaitingSubmission {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request a verification code first"})
+		return
+	}
+	if time.Now().After(verification.CodeExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Your verification code has expired, please request a new one"})
+		return
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open file"})
+		return
+	}
+	data, err := io.ReadAll(src)
+	src.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	fileKey, err := storeVerificationPhoto(ctx, verification, "selfie.jpg", data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	_, err = database.VerificationCollection.UpdateOne(ctx,
+		bson.M{"_id": verification.ID, "status": models.VerificationAwaitingSubmission},
+		bson.M{"$set": bson.M{
+			"status":       models.VerificationPending,
+			"file_key":     fileKey,
+			"submitted_at": now,
+			"updated_at":   now,
+		}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit verification"})
+		return
+	}
+
+	verification.Status = models.VerificationPending
+	verification.SubmittedAt = &now
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "Selfie submitted. An admin will review it shortly",
+		"verification": verification,
+	})
+}
+
+// storeVerificationPhoto re-encodes a submitted photo (dropping its metadata)
+// into the private bucket and returns its key
+func storeVerificationPhoto(ctx context.Context, verification *models.Verification, name string, data []byte) (string, error) {
+	img, _, err := services.DecodeImage(data)
+	if err != nil {
+		return "", err
+	}
+	img = services.ApplyOrientation(services.ResizeToFit(img, verificationPhotoSize), services.JPEGOrientation(data))
+
+	encoded, err := services.EncodeJPEG(img, 90)
+	if err != nil {
+		return "", err
+	}
+
+	store, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		return "", fmt.Errorf("storage configuration missing")
+	}
+
+	// Both IDs may be known to others; the random segment keeps the key secret
+	secret, err := services.RandomKeySegment()
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s%s/%s/%s/%s", services.VerificationPrefix, verification.UserID.Hex(), verification.ID.Hex(), secret, name)
+	if err := store.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), "image/jpeg"); err != nil {
+		return "", fmt.Errorf("failed to store photo: %w", err)
+	}
+	return key, nil
+}
+
+// GetVerificationStatus - GET /auth/verification
+func GetVerificationStatus(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	latest, err := latestVerification(ctx, userObjID, models.VerificationTypeSelfie)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load verification"})
+		return
+	}
+	idDocument, err := latestVerification(ctx, userObjID, models.VerificationTypeIDDocument)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load verification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"verified":        user.VerifiedAt != nil,
+		"verified_at":     user.VerifiedAt,
+		"verification":    latest,
+		"age_verified":    user.AgeVerifiedAt != nil,
+		"age_verified_at": user.AgeVerifiedAt,
+		"date_of_birth":   user.DateOfBirth,
+		"id_document":     idDocument,
+	})
+}
+
+// SubmitIDDocument - POST /auth/verification/id-document (multipart: document, date_of_birth)
+// An admin checks the document against the date of birth before the profile can be listed.
+// date_of_birth is only needed when the profile doesn't have one yet.
+func SubmitIDDocument(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxVerificationBytes+1<<20)
+
+	fileHeader, err := c.FormFile("document")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Document file is required"})
+		return
+	}
+	if fileHeader.Size > maxVerificationBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Document must be %dMB or smaller", maxVerificationBytes>>20)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.AgeVerifiedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your age is already verified"})
+		return
+	}
+
+	latest, err := latestVerification(ctx, userObjID, models.VerificationTypeIDDocument)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load verification"})
+		return
+	}
+	if latest != nil && latest.Status == models.VerificationPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your ID document is already awaiting review", "verification": latest})
+		return
+	}
+
+	now := time.Now()
+	dateOfBirth := user.DateOfBirth
+	if value := c.PostForm("date_of_birth"); value != "" {
+		parsed, err := models.ParseDateOfBirth(value, now)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		dateOfBirth = &parsed
+	}
+	if dateOfBirth == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date_of_birth is required"})
+		return
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open file"})
+		return
+	}
+	data, err := io.ReadAll(src)
+	src.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	verification := models.Verification{
+		ID:          primitive.NewObjectID(),
+		UserID:      userObjID,
+		Type:        models.VerificationTypeIDDocument,
+		DateOfBirth: dateOfBirth,
+		Status:      models.VerificationPending,
+		SubmittedAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	verification.FileKey, err = storeVerificationPhoto(ctx, &verification, "document.jpg", data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := database.VerificationCollection.InsertOne(ctx, verification); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit ID document"})
+		return
+	}
+
+	// Keep the profile in step with the date of birth under review
+	_, err = database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$set": bson.M{
+			"date_of_birth": *dateOfBirth,
+			"age":           models.AgeOn(*dateOfBirth, now),
+			"updated_at":    now,
+		}},
+	)
+	if err != nil {
+		fmt.Printf("⚠️ Could not store date of birth of user %s: %v\n", userObjID.Hex(), err)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "ID document submitted. An admin will review it shortly",
+		"verification": verification,
+	})
+}
@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// profileWatermark is the stamp applied to the public renditions of new photos
+type profileWatermark struct {
+	text     string
+	position string
+}
+
+// watermarkSiteName is the site name shown in watermarks (SITE_NAME, or the SITE_URL host)
+func watermarkSiteName() string {
+	if name := os.Getenv("SITE_NAME"); name != "" {
+		return name
+	}
+	if parsed, err := url.Parse(siteURL()); err == nil && parsed.Host != "" {
+		return strings.TrimPrefix(parsed.Host, "www.")
+	}
+	return "escorthub254.com"
+}
+
+// activePlan returns the plan of the user's current subscription, or nil without one
+func activePlan(ctx context.Context, userObjID primitive.ObjectID) (*models.SubscriptionPlan, error) {
+	var subscription models.Subscription
+	err := database.SubscriptionCollection.FindOne(ctx, bson.M{
+		"user_id":     userObjID,
+		"status":      "active",
+		"expiry_date": bson.M{"$gt": time.Now()},
+	}).Decode(&subscription)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var plan models.SubscriptionPlan
+	err = database.SubscriptionPlanCollection.FindOne(ctx, bson.M{"_id": subscription.PlanID}).Decode(&plan)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// allowedWatermarkPositions lists the placements a plan unlocks; everyone gets the default
+func allowedWatermarkPositions(plan *models.SubscriptionPlan) []string {
+	allowed := []string{services.WatermarkBottomRight}
+	if plan == nil {
+		return allowed
+	}
+	for _, position := range plan.WatermarkPositions {
+		if position != services.WatermarkBottomRight && services.IsWatermarkPosition(position) {
+			allowed = append(allowed, position)
+		}
+	}
+	return allowed
+}
+
+// effectiveWatermark applies the plan's limits to a user's saved settings. A
+// placement the plan no longer allows falls back to the default.
+func effectiveWatermark(settings *models.WatermarkSettings, plan *models.SubscriptionPlan) models.WatermarkSettings {
+	effective := models.WatermarkSettings{Enabled: true, Position: services.WatermarkBottomRight}
+	if settings == nil {
+		return effective
+	}
+
+	effective.Enabled = settings.Enabled
+	for _, position := range allowedWatermarkPositions(plan) {
+		if position == settings.Position {
+			effective.Position = position
+		}
+	}
+	return effective
+}
+
+// userWatermark returns the watermark for new photos of a user, or nil when they turned it off
+func userWatermark(ctx context.Context, userObjID primitive.ObjectID) (*profileWatermark, error) {
+	var user struct {
+		Watermark *models.WatermarkSettings `bson:"watermark"`
+	}
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		return nil, err
+	}
+
+	plan, err := activePlan(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := effectiveWatermark(user.Watermark, plan)
+	if !settings.Enabled {
+		return nil, nil
+	}
+	return &profileWatermark{
+		text:     watermarkSiteName() + "  ID " + userObjID.Hex(),
+		position: settings.Position,
+	}, nil
+}
+
+// GetWatermarkSettings - GET /auth/watermark
+func GetWatermarkSettings(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user struct {
+		Watermark *models.WatermarkSettings `bson:"watermark"`
+	}
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	plan, err := activePlan(ctx, userObjID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load subscription"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"watermark":         effectiveWatermark(user.Watermark, plan),
+		"allowed_positions": allowedWatermarkPositions(plan),
+	})
+}
+
+// UpdateWatermarkSettings - PUT /auth/watermark {"enabled": true, "position": "top-left"}
+func UpdateWatermarkSettings(c *gin.Context) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var request struct {
+		Enabled  *bool  `json:"enabled"`
+		Position string `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user struct {
+		Watermark *models.WatermarkSettings `bson:"watermark"`
+	}
+	if err := database.UserCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	plan, err := activePlan(ctx, userObjID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load subscription"})
+		return
+	}
+
+	settings := effectiveWatermark(user.Watermark, plan)
+	if request.Enabled != nil {
+		settings.Enabled = *request.Enabled
+	}
+	if request.Position != "" {
+		if !services.IsWatermarkPosition(request.Position) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown watermark position", "allowed_positions": allowedWatermarkPositions(plan)})
+			return
+		}
+		allowed := false
+		for _, position := range allowedWatermarkPositions(plan) {
+			if position == request.Position {
+				allowed = true
+			}
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your plan does not include this watermark position", "allowed_positions": allowedWatermarkPositions(plan)})
+			return
+		}
+		settings.Position = request.Position
+	}
+
+	_, err = database.UserCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjID},
+		bson.M{"$set": bson.M{"watermark": settings, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save watermark settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Watermark settings saved. They apply to photos uploaded from now on",
+		"watermark":         settings,
+		"allowed_positions": allowedWatermarkPositions(plan),
+	})
+}
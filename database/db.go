@@ -17,6 +17,15 @@ var Client *mongo.Client
 var UserCollection *mongo.Collection
 var SubscriptionCollection *mongo.Collection
 var SubscriptionPlanCollection *mongo.Collection
+var NotificationCollection *mongo.Collection
+var UploadSessionCollection *mongo.Collection
+var VerificationCollection *mongo.Collection
+var LocationCollection *mongo.Collection
+var ServiceCollection *mongo.Collection
+var BoostOptionCollection *mongo.Collection
+var BoostCollection *mongo.Collection
+var SavedSearchCollection *mongo.Collection
+var ImageHashCollection *mongo.Collection
 
 const DatabaseName = "Inventory"
 
@@ -78,6 +87,15 @@ func ConnectDB() {
 	UserCollection = db.Collection("users")
 	SubscriptionCollection = db.Collection("subscriptions")
 	SubscriptionPlanCollection = db.Collection("subscription_plans")
+	NotificationCollection = db.Collection("notifications")
+	UploadSessionCollection = db.Collection("upload_sessions")
+	VerificationCollection = db.Collection("verifications")
+	LocationCollection = db.Collection("locations")
+	ServiceCollection = db.Collection("services")
+	BoostOptionCollection = db.Collection("boost_options")
+	BoostCollection = db.Collection("boosts")
+	SavedSearchCollection = db.Collection("saved_searches")
+	ImageHashCollection = db.Collection("image_hashes")
 
 	// DEBUG: Check collections
 	fmt.Println("🔍 Checking collections...")
@@ -96,6 +114,15 @@ func ConnectDB() {
 	// Initialize default data (only if subscription_plans is empty)
 	initializeDefaultPlans(ctx)
 
+	// Seed the county -> town -> area taxonomy (only if locations is empty)
+	seedLocations(ctx)
+
+	// Seed the services catalogue (only if services is empty)
+	initializeDefaultServices(ctx)
+
+	// Default boost prices (only if boost_options is empty)
+	initializeDefaultBoostOptions(ctx)
+
 	fmt.Println("✅ Database initialization complete!")
 }
 
@@ -131,6 +158,172 @@ func createIndexes(ctx context.Context, db *mongo.Database) {
 				{Key: "services", Value: 1},
 			},
 		},
+		{
+			Keys: bson.D{
+				{Key: "images.status", Value: 1},
+			},
+		},
+		// Nearby search
+		{
+			Keys: bson.D{
+				{Key: "geo_location", Value: "2dsphere"},
+			},
+		},
+		// Profiles in a county, town or area
+		{
+			Keys: bson.D{
+				{Key: "location_path", Value: 1},
+			},
+		},
+		// Public listing sort orders
+		{
+			Keys: bson.D{
+				{Key: "role", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "role", Value: 1},
+				{Key: "last_active_at", Value: -1},
+			},
+		},
+		// Profile text search, names weigh the most. Search terms are expanded
+		// in the app, so words are indexed as written, without stemming.
+		{
+			Keys: bson.D{
+				{Key: "first_name", Value: "text"},
+				{Key: "last_name", Value: "text"},
+				{Key: "location", Value: "text"},
+				{Key: "services", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("profile_text").
+				SetDefaultLanguage("none").
+				SetWeights(bson.M{
+					"first_name": 10,
+					"last_name":  10,
+					"location":   5,
+					"services":   3,
+				}),
+		},
+	}
+
+	// Index for notifications (a user's inbox, newest first)
+	notificationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	}
+
+	// Index for resumable uploads (per-user quota and expiry cleanup)
+	uploadSessionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "expires_at", Value: 1},
+			},
+		},
+	}
+
+	// Index for verifications (a user's attempts and the admin review queue)
+	verificationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "submitted_at", Value: 1},
+			},
+		},
+	}
+
+	// Index for locations (slug and alias lookups, children of a place)
+	locationIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "keys", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "parent_id", Value: 1},
+				{Key: "name", Value: 1},
+			},
+		},
+	}
+
+	// Index for boosts (price lookup per location, payment callbacks, a user's boosts)
+	boostOptionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "location_id", Value: 1},
+				{Key: "duration_hours", Value: 1},
+			},
+		},
+	}
+	boostIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "checkout_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	}
+
+	// Indexes for clients' saved searches
+	savedSearchIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "is_active", Value: 1}},
+		},
+	}
+
+	// Index for photo hashes: duplicate lookups match any band
+	imageHashIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "bands", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "image_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	}
+
+	// Index for the services catalogue
+	serviceIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "keys", Value: 1}},
+		},
 	}
 
 	// Create indexes for subscriptions
@@ -146,6 +339,132 @@ func createIndexes(ctx context.Context, db *mongo.Database) {
 	} else {
 		fmt.Println("✅ User indexes created")
 	}
+
+	// Create indexes for notifications
+	if _, err := NotificationCollection.Indexes().CreateMany(ctx, notificationIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create notification indexes: %v", err)
+	} else {
+		fmt.Println("✅ Notification indexes created")
+	}
+
+	// Create indexes for upload sessions
+	if _, err := UploadSessionCollection.Indexes().CreateMany(ctx, uploadSessionIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create upload session indexes: %v", err)
+	} else {
+		fmt.Println("✅ Upload session indexes created")
+	}
+
+	// Create indexes for verifications
+	if _, err := VerificationCollection.Indexes().CreateMany(ctx, verificationIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create verification indexes: %v", err)
+	} else {
+		fmt.Println("✅ Verification indexes created")
+	}
+
+	// Create indexes for locations
+	if _, err := LocationCollection.Indexes().CreateMany(ctx, locationIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create location indexes: %v", err)
+	} else {
+		fmt.Println("✅ Location indexes created")
+	}
+
+	// Create indexes for services
+	if _, err := ServiceCollection.Indexes().CreateMany(ctx, serviceIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create service indexes: %v", err)
+	} else {
+		fmt.Println("✅ Service indexes created")
+	}
+
+	// Create indexes for boosts
+	if _, err := BoostOptionCollection.Indexes().CreateMany(ctx, boostOptionIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create boost option indexes: %v", err)
+	} else {
+		fmt.Println("✅ Boost option indexes created")
+	}
+	if _, err := BoostCollection.Indexes().CreateMany(ctx, boostIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create boost indexes: %v", err)
+	} else {
+		fmt.Println("✅ Boost indexes created")
+	}
+
+	// Create indexes for saved searches
+	if _, err := SavedSearchCollection.Indexes().CreateMany(ctx, savedSearchIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create saved search indexes: %v", err)
+	} else {
+		fmt.Println("✅ Saved search indexes created")
+	}
+
+	// Create indexes for photo hashes
+	if _, err := ImageHashCollection.Indexes().CreateMany(ctx, imageHashIndexes); err != nil {
+		log.Printf("⚠️ Warning: Could not create image hash indexes: %v", err)
+	} else {
+		fmt.Println("✅ Image hash indexes created")
+	}
+}
+
+// initializeDefaultBoostOptions creates the boost prices used where admins
+// haven't configured a location
+func initializeDefaultBoostOptions(ctx context.Context) {
+	count, err := BoostOptionCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		log.Printf("⚠️ Warning: Could not count boost options: %v", err)
+		return
+	}
+	if count > 0 {
+		fmt.Println("✅ Boost options already exist")
+		return
+	}
+
+	now := time.Now()
+	defaults := []interface{}{
+		bson.M{"amount": 50.0, "duration_hours": 3, "is_active": true, "created_at": now, "updated_at": now},
+		bson.M{"amount": 150.0, "duration_hours": 12, "is_active": true, "created_at": now, "updated_at": now},
+	}
+	if _, err := BoostOptionCollection.InsertMany(ctx, defaults); err != nil {
+		log.Printf("⚠️ Warning: Could not insert default boost options: %v", err)
+	} else {
+		fmt.Println("✅ Default boost options created")
+	}
+}
+
+// Watermark placements unlocked by the paid default plans
+var (
+	proWatermarkPositions     = []string{"bottom-right", "bottom-left"}
+	premiumWatermarkPositions = []string{"bottom-right", "bottom-left", "top-right", "top-left", "center"}
+)
+
+// Listing placement of the default plans: rank weight and featured slots per page
+var defaultPlanPlacement = map[string][2]int{
+	"5-Day Basic":     {1, 0},
+	"2-Week Pro":      {2, 1},
+	"1-Month Premium": {3, 2},
+}
+
+// upgradeDefaultPlans adds settings introduced after the default plans were first created
+func upgradeDefaultPlans(ctx context.Context) {
+	upgrades := map[string][]string{
+		"2-Week Pro":      proWatermarkPositions,
+		"1-Month Premium": premiumWatermarkPositions,
+	}
+	for name, positions := range upgrades {
+		_, err := SubscriptionPlanCollection.UpdateOne(ctx,
+			bson.M{"name": name, "watermark_positions": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"watermark_positions": positions}},
+		)
+		if err != nil {
+			log.Printf("⚠️ Warning: Could not upgrade plan %s: %v", name, err)
+		}
+	}
+
+	for name, placement := range defaultPlanPlacement {
+		_, err := SubscriptionPlanCollection.UpdateOne(ctx,
+			bson.M{"name": name, "rank_weight": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"rank_weight": placement[0], "featured_slots": placement[1]}},
+		)
+		if err != nil {
+			log.Printf("⚠️ Warning: Could not upgrade plan %s: %v", name, err)
+		}
+	}
 }
 
 // Updated initializeDefaultPlans function with context parameter
@@ -159,6 +478,7 @@ func initializeDefaultPlans(ctx context.Context) {
 
 	if count > 0 {
 		fmt.Println("✅ Subscription plans already exist")
+		upgradeDefaultPlans(ctx)
 		return
 	}
 
@@ -170,6 +490,11 @@ func initializeDefaultPlans(ctx context.Context) {
 		Description  string    `bson:"description"`
 		IsActive     bool      `bson:"is_active"`
 		CreatedAt    time.Time `bson:"created_at"`
+
+		WatermarkPositions []string `bson:"watermark_positions,omitempty"`
+
+		RankWeight    int `bson:"rank_weight"`
+		FeaturedSlots int `bson:"featured_slots,omitempty"`
 	}
 
 	plans := []interface{}{
@@ -180,6 +505,8 @@ func initializeDefaultPlans(ctx context.Context) {
 			Description:  "Basic visibility for 5 days",
 			IsActive:     true,
 			CreatedAt:    time.Now(),
+
+			RankWeight: defaultPlanPlacement["5-Day Basic"][0],
 		},
 		Plan{
 			Name:         "2-Week Pro",
@@ -188,6 +515,10 @@ func initializeDefaultPlans(ctx context.Context) {
 			Description:  "Better visibility for 2 weeks",
 			IsActive:     true,
 			CreatedAt:    time.Now(),
+
+			WatermarkPositions: proWatermarkPositions,
+			RankWeight:         defaultPlanPlacement["2-Week Pro"][0],
+			FeaturedSlots:      defaultPlanPlacement["2-Week Pro"][1],
 		},
 		Plan{
 			Name:         "1-Month Premium",
@@ -196,6 +527,10 @@ func initializeDefaultPlans(ctx context.Context) {
 			Description:  "Maximum visibility for 1 month",
 			IsActive:     true,
 			CreatedAt:    time.Now(),
+
+			WatermarkPositions: premiumWatermarkPositions,
+			RankWeight:         defaultPlanPlacement["1-Month Premium"][0],
+			FeaturedSlots:      defaultPlanPlacement["1-Month Premium"][1],
 		},
 	}
 
@@ -0,0 +1,405 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Location levels
+const (
+	LocationCounty = "county"
+	LocationTown   = "town"
+	LocationArea   = "area"
+)
+
+type seedArea struct {
+	Name     string
+	Lat, Lng float64
+	Aliases  []string
+}
+
+type seedTown struct {
+	Name     string
+	Lat, Lng float64
+	Aliases  []string
+	Areas    []seedArea
+}
+
+type seedCounty struct {
+	Code    int
+	Name    string
+	Aliases []string
+	Towns   []seedTown // The first town is the county headquarters
+}
+
+// kenyaLocations lists the 47 counties with their main towns and the
+// neighbourhoods providers most often advertise in
+var kenyaLocations = []seedCounty{
+	{1, "Mombasa", []string{"msa"}, []seedTown{
+		{"Mombasa", -4.0435, 39.6682, []string{"mombasa cbd", "mombasa island"}, []seedArea{
+			{"Nyali", -4.0230, 39.7100, nil},
+			{"Bamburi", -3.9960, 39.7210, nil},
+			{"Shanzu", -3.9500, 39.7480, nil},
+			{"Kisauni", -4.0100, 39.6950, nil},
+			{"Tudor", -4.0390, 39.6780, nil},
+			{"Likoni", -4.0890, 39.6600, nil},
+			{"Changamwe", -4.0240, 39.6270, nil},
+		}},
+	}},
+	{2, "Kwale", nil, []seedTown{
+		{"Kwale", -4.1816, 39.4606, nil, nil},
+		{"Ukunda", -4.2876, 39.5660, nil, []seedArea{
+			{"Diani", -4.2800, 39.5800, []string{"diani beach"}},
+		}},
+		{"Msambweni", -4.4667, 39.4833, nil, nil},
+	}},
+	{3, "Kilifi", nil, []seedTown{
+		{"Kilifi", -3.6305, 39.8499, nil, nil},
+		{"Malindi", -3.2192, 40.1169, nil, nil},
+		{"Watamu", -3.3540, 40.0240, nil, nil},
+		{"Mtwapa", -3.9500, 39.7333, nil, nil},
+		{"Mariakani", -3.8667, 39.4667, nil, nil},
+	}},
+	{4, "Tana River", nil, []seedTown{
+		{"Hola", -1.5000, 40.0300, nil, nil},
+	}},
+	{5, "Lamu", nil, []seedTown{
+		{"Lamu", -2.2717, 40.9020, nil, nil},
+		{"Mpeketoni", -2.3900, 40.7000, nil, nil},
+	}},
+	{6, "Taita-Taveta", []string{"taita taveta"}, []seedTown{
+		{"Mwatate", -3.5050, 38.3780, nil, nil},
+		{"Voi", -3.3961, 38.5561, nil, nil},
+		{"Taveta", -3.3980, 37.6830, nil, nil},
+		{"Wundanyi", -3.4019, 38.3600, nil, nil},
+	}},
+	{7, "Garissa", nil, []seedTown{
+		{"Garissa", -0.4532, 39.6461, nil, nil},
+	}},
+	{8, "Wajir", nil, []seedTown{
+		{"Wajir", 1.7471, 40.0573, nil, nil},
+	}},
+	{9, "Mandera", nil, []seedTown{
+		{"Mandera", 3.9366, 41.8670, nil, nil},
+	}},
+	{10, "Marsabit", nil, []seedTown{
+		{"Marsabit", 2.3284, 37.9899, nil, nil},
+		{"Moyale", 3.5167, 39.0584, nil, nil},
+	}},
+	{11, "Isiolo", nil, []seedTown{
+		{"Isiolo", 0.3546, 37.5822, nil, nil},
+	}},
+	{12, "Meru", nil, []seedTown{
+		{"Meru", 0.0470, 37.6490, nil, nil},
+		{"Maua", 0.2333, 37.9333, nil, nil},
+		{"Timau", 0.0833, 37.2333, nil, nil},
+	}},
+	{13, "Tharaka-Nithi", []string{"tharaka nithi"}, []seedTown{
+		{"Kathwana", -0.2833, 37.8667, nil, nil},
+		{"Chuka", -0.3333, 37.6500, nil, nil},
+	}},
+	{14, "Embu", nil, []seedTown{
+		{"Embu", -0.5389, 37.4574, nil, nil},
+		{"Runyenjes", -0.4167, 37.5667, nil, nil},
+	}},
+	{15, "Kitui", nil, []seedTown{
+		{"Kitui", -1.3670, 38.0106, nil, nil},
+		{"Mwingi", -0.9333, 38.0667, nil, nil},
+	}},
+	{16, "Machakos", nil, []seedTown{
+		{"Machakos", -1.5177, 37.2634, nil, nil},
+		{"Athi River", -1.4561, 36.9786, []string{"mavoko"}, nil},
+		{"Syokimau", -1.3570, 36.9320, nil, nil},
+		{"Mlolongo", -1.3940, 36.9400, nil, nil},
+		{"Kangundo", -1.3000, 37.3500, nil, nil},
+	}},
+	{17, "Makueni", nil, []seedTown{
+		{"Wote", -1.7833, 37.6333, nil, nil},
+		{"Emali", -2.0833, 37.4667, nil, nil},
+		{"Makindu", -2.2833, 37.8333, nil, nil},
+	}},
+	{18, "Nyandarua", nil, []seedTown{
+		{"Ol Kalou", -0.2667, 36.3833, []string{"olkalou"}, nil},
+		{"Engineer", -0.6000, 36.5500, nil, nil},
+	}},
+	{19, "Nyeri", nil, []seedTown{
+		{"Nyeri", -0.4201, 36.9476, nil, nil},
+		{"Karatina", -0.4833, 37.1333, nil, nil},
+		{"Othaya", -0.5500, 36.9500, nil, nil},
+	}},
+	{20, "Kirinyaga", nil, []seedTown{
+		{"Kerugoya", -0.4989, 37.2803, nil, nil},
+		{"Kutus", -0.5667, 37.3167, nil, nil},
+		{"Wang'uru", -0.6833, 37.3667, []string{"wanguru", "mwea"}, nil},
+	}},
+	{21, "Murang'a", []string{"muranga"}, []seedTown{
+		{"Murang'a", -0.7210, 37.1526, []string{"muranga"}, nil},
+		{"Kenol", -0.9000, 37.1500, []string{"makuyu"}, nil},
+	}},
+	{22, "Kiambu", nil, []seedTown{
+		{"Kiambu", -1.1714, 36.8356, nil, nil},
+		{"Thika", -1.0333, 37.0693, nil, []seedArea{
+			{"Makongeni", -1.0450, 37.0900, nil},
+			{"Section 9", -1.0380, 37.0780, nil},
+		}},
+		{"Ruiru", -1.1460, 36.9610, nil, nil},
+		{"Juja", -1.1020, 37.0140, nil, nil},
+		{"Githurai", -1.2000, 36.9167, nil, nil},
+		{"Ruaka", -1.2050, 36.7830, nil, nil},
+		{"Kikuyu", -1.2464, 36.6630, nil, nil},
+		{"Limuru", -1.1136, 36.6422, nil, nil},
+	}},
+	{23, "Turkana", nil, []seedTown{
+		{"Lodwar", 3.1191, 35.5973, nil, nil},
+		{"Kakuma", 3.7167, 34.8667, nil, nil},
+	}},
+	{24, "West Pokot", nil, []seedTown{
+		{"Kapenguria", 1.2389, 35.1119, nil, nil},
+		{"Makutano", 1.2500, 35.1000, nil, nil},
+	}},
+	{25, "Samburu", nil, []seedTown{
+		{"Maralal", 1.0968, 36.6980, nil, nil},
+	}},
+	{26, "Trans-Nzoia", []string{"trans nzoia"}, []seedTown{
+		{"Kitale", 1.0157, 35.0062, nil, nil},
+	}},
+	{27, "Uasin Gishu", nil, []seedTown{
+		{"Eldoret", 0.5143, 35.2698, []string{"eldy"}, []seedArea{
+			{"Elgon View", 0.5100, 35.2900, nil},
+			{"Kapsoya", 0.5300, 35.3000, nil},
+			{"Pioneer", 0.5000, 35.2900, nil},
+		}},
+		{"Burnt Forest", 0.2333, 35.4333, nil, nil},
+	}},
+	{28, "Elgeyo-Marakwet", []string{"elgeyo marakwet"}, []seedTown{
+		{"Iten", 0.6703, 35.5081, nil, nil},
+	}},
+	{29, "Nandi", nil, []seedTown{
+		{"Kapsabet", 0.2039, 35.1050, nil, nil},
+		{"Nandi Hills", 0.1000, 35.1833, nil, nil},
+	}},
+	{30, "Baringo", nil, []seedTown{
+		{"Kabarnet", 0.4919, 35.7430, nil, nil},
+		{"Eldama Ravine", 0.0500, 35.7167, nil, nil},
+	}},
+	{31, "Laikipia", nil, []seedTown{
+		{"Rumuruti", 0.2667, 36.5333, nil, nil},
+		{"Nanyuki", 0.0167, 37.0667, nil, nil},
+		{"Nyahururu", 0.0380, 36.3630, []string{"thomson falls"}, nil},
+	}},
+	{32, "Nakuru", []string{"nku"}, []seedTown{
+		{"Nakuru", -0.3031, 36.0800, nil, []seedArea{
+			{"Milimani", -0.2900, 36.0700, nil},
+			{"Section 58", -0.2880, 36.0900, nil},
+			{"Lanet", -0.3000, 36.1500, nil},
+			{"Free Area", -0.3000, 36.1100, nil},
+		}},
+		{"Naivasha", -0.7167, 36.4333, nil, nil},
+		{"Gilgil", -0.4986, 36.3167, nil, nil},
+		{"Molo", -0.2490, 35.7320, nil, nil},
+		{"Njoro", -0.3300, 35.9440, nil, nil},
+	}},
+	{33, "Narok", nil, []seedTown{
+		{"Narok", -1.0783, 35.8601, nil, nil},
+		{"Kilgoris", -1.0000, 34.8833, nil, nil},
+	}},
+	{34, "Kajiado", nil, []seedTown{
+		{"Kajiado", -1.8524, 36.7768, nil, nil},
+		{"Kitengela", -1.4770, 36.9600, nil, nil},
+		{"Ongata Rongai", -1.3960, 36.7450, []string{"rongai"}, nil},
+		{"Ngong", -1.3524, 36.6599, nil, nil},
+		{"Kiserian", -1.4200, 36.6900, nil, nil},
+		{"Namanga", -2.5500, 36.7833, nil, nil},
+		{"Loitokitok", -2.9333, 37.5167, nil, nil},
+	}},
+	{35, "Kericho", nil, []seedTown{
+		{"Kericho", -0.3677, 35.2831, nil, nil},
+		{"Litein", -0.5833, 35.1833, nil, nil},
+	}},
+	{36, "Bomet", nil, []seedTown{
+		{"Bomet", -0.7813, 35.3416, nil, nil},
+		{"Sotik", -0.6833, 35.1167, nil, nil},
+	}},
+	{37, "Kakamega", nil, []seedTown{
+		{"Kakamega", 0.2827, 34.7519, nil, nil},
+		{"Mumias", 0.3358, 34.4886, nil, nil},
+	}},
+	{38, "Vihiga", nil, []seedTown{
+		{"Mbale", 0.0667, 34.7167, nil, nil},
+		{"Luanda", 0.0167, 34.5833, nil, nil},
+	}},
+	{39, "Bungoma", nil, []seedTown{
+		{"Bungoma", 0.5635, 34.5606, nil, nil},
+		{"Webuye", 0.6167, 34.7667, nil, nil},
+		{"Kimilili", 0.7833, 34.7167, nil, nil},
+	}},
+	{40, "Busia", nil, []seedTown{
+		{"Busia", 0.4608, 34.1115, nil, nil},
+		{"Malaba", 0.6333, 34.2833, nil, nil},
+	}},
+	{41, "Siaya", nil, []seedTown{
+		{"Siaya", 0.0607, 34.2881, nil, nil},
+		{"Bondo", -0.1000, 34.2667, nil, nil},
+	}},
+	{42, "Kisumu", []string{"ksm"}, []seedTown{
+		{"Kisumu", -0.0917, 34.7680, []string{"kisumu cbd"}, []seedArea{
+			{"Milimani", -0.1000, 34.7550, nil},
+			{"Mamboleo", -0.0700, 34.7900, nil},
+			{"Nyalenda", -0.1100, 34.7700, nil},
+			{"Kondele", -0.0850, 34.7800, nil},
+		}},
+		{"Ahero", -0.1667, 34.9167, nil, nil},
+		{"Maseno", 0.0000, 34.6000, nil, nil},
+	}},
+	{43, "Homa Bay", nil, []seedTown{
+		{"Homa Bay", -0.5273, 34.4571, nil, nil},
+		{"Mbita", -0.4167, 34.2000, nil, nil},
+	}},
+	{44, "Migori", nil, []seedTown{
+		{"Migori", -1.0634, 34.4731, nil, nil},
+		{"Awendo", -0.9000, 34.5333, nil, nil},
+		{"Rongo", -0.7667, 34.6000, nil, nil},
+		{"Isebania", -1.2333, 34.4833, nil, nil},
+	}},
+	{45, "Kisii", nil, []seedTown{
+		{"Kisii", -0.6817, 34.7667, nil, nil},
+		{"Ogembo", -0.8000, 34.7167, nil, nil},
+	}},
+	{46, "Nyamira", nil, []seedTown{
+		{"Nyamira", -0.5633, 34.9358, nil, nil},
+		{"Keroka", -0.7667, 34.9333, nil, nil},
+	}},
+	{47, "Nairobi", []string{"nbi", "nrb", "nairobi county"}, []seedTown{
+		{"Nairobi", -1.2864, 36.8172, []string{"nairobi cbd", "cbd"}, []seedArea{
+			{"Westlands", -1.2676, 36.8108, nil},
+			{"Parklands", -1.2610, 36.8170, nil},
+			{"Kilimani", -1.2921, 36.7839, nil},
+			{"Kileleshwa", -1.2786, 36.7800, nil},
+			{"Lavington", -1.2787, 36.7675, nil},
+			{"Hurlingham", -1.2950, 36.7950, nil},
+			{"Upper Hill", -1.2990, 36.8150, []string{"upperhill"}},
+			{"Karen", -1.3194, 36.7073, nil},
+			{"Langata", -1.3500, 36.7500, []string{"lang'ata"}},
+			{"Madaraka", -1.3080, 36.8200, nil},
+			{"South B", -1.3090, 36.8370, nil},
+			{"South C", -1.3200, 36.8260, nil},
+			{"Gigiri", -1.2330, 36.8050, nil},
+			{"Runda", -1.2170, 36.8100, nil},
+			{"Muthaiga", -1.2500, 36.8300, nil},
+			{"Ngara", -1.2750, 36.8250, nil},
+			{"Pangani", -1.2700, 36.8400, nil},
+			{"Eastleigh", -1.2740, 36.8500, nil},
+			{"Kasarani", -1.2210, 36.8970, nil},
+			{"Roysambu", -1.2180, 36.8850, []string{"trm"}},
+			{"Kahawa", -1.1850, 36.9200, []string{"kahawa west", "kahawa sukari"}},
+			{"Ruaraka", -1.2450, 36.8750, nil},
+			{"Buruburu", -1.2850, 36.8800, []string{"buru buru"}},
+			{"Donholm", -1.2950, 36.8900, nil},
+			{"Umoja", -1.2850, 36.9000, nil},
+			{"Embakasi", -1.3200, 36.9000, nil},
+			{"Utawala", -1.2900, 36.9600, nil},
+			{"Kayole", -1.2750, 36.9150, nil},
+		}},
+	}},
+}
+
+// seedLocations fills the locations collection with the county -> town -> area
+// taxonomy the first time the app starts against an empty collection
+func seedLocations(ctx context.Context) {
+	count, err := LocationCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		log.Printf("⚠️ Warning: Could not count locations: %v", err)
+		return
+	}
+	if count > 0 {
+		fmt.Println("✅ Locations already exist")
+		return
+	}
+
+	now := time.Now()
+	slugs := map[string]bool{}
+	var docs []interface{}
+
+	// uniqueSlug keeps slugs unique across levels: a town sharing its county's
+	// name becomes "nakuru-town", an area name used twice gets its town appended
+	uniqueSlug := func(name, level, parentSlug string) string {
+		slug := models.Slugify(name)
+		if slugs[slug] {
+			if level == LocationTown {
+				slug += "-town"
+			} else {
+				slug += "-" + parentSlug
+			}
+		}
+		slugs[slug] = true
+		return slug
+	}
+
+	add := func(name, level, slug, label string, code int, parentID *primitive.ObjectID, ancestors []primitive.ObjectID, aliases []string, lat, lng float64) primitive.ObjectID {
+		id := primitive.NewObjectID()
+
+		// Lookup keys: everything a user might type for this place
+		keys := []string{slug, strings.ToLower(name)}
+		for _, alias := range aliases {
+			keys = append(keys, strings.ToLower(alias))
+		}
+
+		doc := bson.M{
+			"_id":        id,
+			"name":       name,
+			"slug":       slug,
+			"type":       level,
+			"level":      len(ancestors), // 0 county, 1 town, 2 area
+			"label":      label,
+			"ancestors":  ancestors,
+			"path":       append(append([]primitive.ObjectID{}, ancestors...), id),
+			"aliases":    aliases,
+			"keys":       keys,
+			"center":     bson.M{"type": "Point", "coordinates": bson.A{lng, lat}},
+			"is_active":  true,
+			"created_at": now,
+		}
+		if code > 0 {
+			doc["code"] = code
+		}
+		if parentID != nil {
+			doc["parent_id"] = *parentID
+		}
+		docs = append(docs, doc)
+		return id
+	}
+
+	for _, county := range kenyaLocations {
+		hq := county.Towns[0]
+		countySlug := uniqueSlug(county.Name, LocationCounty, "")
+		countyID := add(county.Name, LocationCounty, countySlug, county.Name, county.Code, nil, nil, county.Aliases, hq.Lat, hq.Lng)
+
+		for _, town := range county.Towns {
+			townSlug := uniqueSlug(town.Name, LocationTown, countySlug)
+			townLabel := town.Name + ", " + county.Name
+			if town.Name == county.Name {
+				townLabel = town.Name
+			}
+			townID := add(town.Name, LocationTown, townSlug, townLabel, 0, &countyID, []primitive.ObjectID{countyID}, town.Aliases, town.Lat, town.Lng)
+
+			for _, area := range town.Areas {
+				areaSlug := uniqueSlug(area.Name, LocationArea, townSlug)
+				add(area.Name, LocationArea, areaSlug, area.Name+", "+town.Name, 0, &townID, []primitive.ObjectID{countyID, townID}, area.Aliases, area.Lat, area.Lng)
+			}
+		}
+	}
+
+	if _, err := LocationCollection.InsertMany(ctx, docs); err != nil {
+		log.Printf("⚠️ Warning: Could not seed locations: %v", err)
+		return
+	}
+	fmt.Printf("✅ Seeded %d locations\n", len(docs))
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// defaultServices is the starting services catalogue; admins manage it afterwards
+var defaultServices = []struct {
+	Name     string
+	Category string
+	Aliases  []string
+}{
+	{"Dinner Date", "Companionship", []string{"dinner", "dinner dates", "date"}},
+	{"Event Companion", "Companionship", []string{"events", "party", "parties"}},
+	{"Travel Companion", "Companionship", []string{"travel", "travelling", "trips"}},
+	{"Overnight", "Companionship", []string{"overnight stay", "night", "full night"}},
+	{"Girlfriend Experience", "Companionship", []string{"gfe"}},
+	{"Couples", "Companionship", []string{"couple", "couples friendly"}},
+	{"Massage", "Massage", []string{"massages", "body massage"}},
+	{"Full Body Massage", "Massage", []string{"full body"}},
+	{"Nuru Massage", "Massage", []string{"nuru"}},
+	{"Incall", "Meeting", []string{"in call", "my place"}},
+	{"Outcall", "Meeting", []string{"out call", "hotel visits", "your place"}},
+	{"Video Call", "Online", []string{"video calls", "video chat", "sexting"}},
+}
+
+// ServiceKeys lists the lowercase values a service can be looked up by
+func ServiceKeys(name, slug string, aliases []string) []string {
+	keys := []string{slug, strings.ToLower(strings.TrimSpace(name))}
+	for _, alias := range aliases {
+		if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+			keys = append(keys, alias)
+			// Links to a service by a former name use its slug
+			if aliasSlug := models.Slugify(alias); aliasSlug != "" && aliasSlug != alias {
+				keys = append(keys, aliasSlug)
+			}
+		}
+	}
+	return keys
+}
+
+// initializeDefaultServices seeds the services catalogue (only if it is empty)
+func initializeDefaultServices(ctx context.Context) {
+	count, err := ServiceCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		log.Printf("⚠️ Warning: Could not count services: %v", err)
+		return
+	}
+	if count > 0 {
+		fmt.Println("✅ Services catalogue already exists")
+		return
+	}
+
+	now := time.Now()
+	var docs []interface{}
+	for _, service := range defaultServices {
+		slug := models.Slugify(service.Name)
+		docs = append(docs, bson.M{
+			"name":       service.Name,
+			"slug":       slug,
+			"category":   service.Category,
+			"aliases":    service.Aliases,
+			"keys":       ServiceKeys(service.Name, slug, service.Aliases),
+			"is_active":  true,
+			"created_at": now,
+			"updated_at": now,
+		})
+	}
+
+	if _, err := ServiceCollection.InsertMany(ctx, docs); err != nil {
+		log.Printf("⚠️ Warning: Could not seed services: %v", err)
+		return
+	}
+	fmt.Printf("✅ Seeded %d services\n", len(docs))
+}
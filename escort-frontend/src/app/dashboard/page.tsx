@@ -4,6 +4,16 @@ import { useState, useEffect, useRef } from 'react';
 import { useRouter } from 'next/navigation';
 import Link from 'next/link';
 
+interface ProfileImage {
+  id: string;
+  thumbnail: string;
+  card: string;
+  full: string;
+  caption?: string;
+  status?: 'pending' | 'approved' | 'rejected';
+  rejection_reason?: string;
+}
+
 interface User {
   _id?: string;
   id?: string;
@@ -18,7 +28,7 @@ interface User {
   location: string;
   services: string[];
   image_url?: string;
-  images?: string[];
+  images?: ProfileImage[];
   is_active: boolean;
   role: string;
   has_subscription: boolean;
@@ -151,18 +161,24 @@ export default function DashboardPage() {
     return url;
   };
 
-  // Process images - fix broken URLs
-  let images: string[] = [];
+  // Photos come as renditions; fix broken URLs in each
+  let images: ProfileImage[] = [];
   if (Array.isArray(data.user.images) && data.user.images.length > 0) {
-    images = data.user.images.map(fixImageUrl).filter((url: string) => url !== '');
-  } else if (data.user.image_url) {
-    images = [fixImageUrl(data.user.image_url)];
+    images = data.user.images
+      .filter((image: any) => image && typeof image === 'object')
+      .map((image: ProfileImage) => ({
+        ...image,
+        thumbnail: fixImageUrl(image.thumbnail),
+        card: fixImageUrl(image.card),
+        full: fixImageUrl(image.full),
+      }))
+      .filter((image: ProfileImage) => image.card !== '');
   }
   
   const userData = {
     ...data.user,
     images: images,
-    image_url: images[0] || '',
+    image_url: fixImageUrl(data.user.image_url),
     services: Array.isArray(data.user.services) ? data.user.services : [],
     is_active: data.user.is_active || false,
     has_subscription: data.user.has_subscription || false,
@@ -276,15 +292,15 @@ export default function DashboardPage() {
       const data = await response.json();
       console.log('Upload response:', data);
 
-      if (data.success && data.imageUrls && data.imageUrls.length > 0) {
-        // Update user with all images
-        const updatedImages = [...currentImages, ...data.imageUrls];
+      if (data.success && Array.isArray(data.images)) {
+        // The response holds all of the user's photos, new ones pending review
+        const uploadedCount = Array.isArray(data.uploaded) ? data.uploaded.length : filesToUpload.length;
         setUser({
           ...user,
-          images: updatedImages,
-          image_url: updatedImages[0] || ''
+          images: data.images,
+          image_url: data.image_url || ''
         });
-        alert(`Successfully uploaded ${data.imageUrls.length} photo(s)! Total: ${updatedImages.length}/${MAX_IMAGES}`);
+        alert(`Successfully uploaded ${uploadedCount} photo(s)! They will appear on your profile once approved. Total: ${data.images.length}/${MAX_IMAGES}`);
         await fetchUserData(); // Refresh to get updated data
       } else {
         throw new Error(data.error || 'Failed to upload images');
@@ -303,8 +319,8 @@ export default function DashboardPage() {
  const handleDeleteImage = async (index: number) => {
   if (!user || !user.images || index >= user.images.length) return;
   
-  const imageUrl = user.images[index];
-  if (!imageUrl) return;
+  const image = user.images[index];
+  if (!image) return;
   
   // Confirm deletion
   if (!window.confirm('Are you sure you want to delete this photo? This action cannot be undone.')) {
@@ -320,16 +336,13 @@ export default function DashboardPage() {
       return;
     }
     
-    // ⬇️⬇️⬇️ PERMANENT FIX: Send only imageUrl ⬇️⬇️⬇️
     const response = await fetch(`${BACKEND_URL}/auth/delete-image`, {
       method: 'DELETE',
       headers: {
         'Authorization': `Bearer ${token}`,
         'Content-Type': 'application/json',
       },
-      body: JSON.stringify({ 
-        imageUrl: imageUrl  // ⬅️ ONLY imageUrl, NO filename
-      }),
+      body: JSON.stringify(image.id ? { imageId: image.id } : { imageUrl: image.full }),
     });
     
     if (!response.ok) {
@@ -340,14 +353,11 @@ export default function DashboardPage() {
     const data = await response.json();
     
     if (data.success) {
-      // Update local state to remove the image
-      const updatedImages = [...user.images];
-      updatedImages.splice(index, 1);
-      
+      // The response holds the remaining photos and the new cover image
       setUser({
         ...user,
-        images: updatedImages,
-        image_url: updatedImages[0] || ''
+        images: Array.isArray(data.images) ? data.images : [],
+        image_url: data.image_url || ''
       });
       
       alert('Photo deleted successfully!');
@@ -366,7 +376,7 @@ export default function DashboardPage() {
     setUser({
       ...user!,
       images: updatedImages,
-      image_url: updatedImages[0] || ''
+      image_url: updatedImages[0]?.card || ''
     });
     
     alert('Photo removed from your profile, but may still exist on the server.');
@@ -756,12 +766,12 @@ export default function DashboardPage() {
                           <div className="relative">
                             <div className="w-full h-48 rounded-lg overflow-hidden border border-gray-200">
                               <img
-                                src={images[0]}
+                                src={images[0].card}
                                 alt="Profile"
                                 className="w-full h-full object-cover"
                               />
                               <div className="absolute bottom-2 left-2 bg-black bg-opacity-50 text-white text-xs px-2 py-1 rounded">
-                                Main Photo
+                                Main Photo{images[0].status && images[0].status !== 'approved' ? ` (${images[0].status})` : ''}
                               </div>
                             </div>
                             <button
@@ -788,10 +798,10 @@ export default function DashboardPage() {
                         {/* Additional Photos */}
                         <div className="grid grid-cols-2 gap-4 col-span-2">
                           {images.slice(1).map((image, index) => (
-                            <div key={index + 1} className="relative">
+                            <div key={image.id || index + 1} className="relative">
                               <div className="aspect-square w-full rounded-lg overflow-hidden border border-gray-200">
                                 <img
-                                  src={image}
+                                  src={image.card}
                                   alt={`Profile ${index + 2}`}
                                   className="w-full h-full object-cover"
                                 />
@@ -815,7 +825,7 @@ export default function DashboardPage() {
                                 )}
                               </button>
                               <div className="absolute bottom-1 left-1 bg-black bg-opacity-50 text-white text-xs px-1 py-0.5 rounded">
-                                Photo {index + 2}
+                                Photo {index + 2}{image.status && image.status !== 'approved' ? ` (${image.status})` : ''}
                               </div>
                             </div>
                           ))}
@@ -3,6 +3,23 @@
 import { useState, useEffect } from 'react';
 import { useParams, useRouter } from 'next/navigation';
 
+interface ProfileImage {
+  id: string;
+  thumbnail: string;
+  card: string;
+  full: string;
+  caption?: string;
+}
+
+// Photos come as renditions; a bare URL (the cover image) stands for all of them
+const toProfileImages = (images: unknown, imageUrl?: string): ProfileImage[] => {
+  if (Array.isArray(images)) {
+    return images.filter(
+      (image): image is ProfileImage => !!image && typeof image === 'object' && typeof image.full === 'string'
+    );
+  }
+  return imageUrl ? [{ id: '', thumbnail: imageUrl, card: imageUrl, full: imageUrl }] : [];
+};
 
 interface Provider {
   _id: string;
@@ -12,7 +29,7 @@ interface Provider {
   phone_no: string;
   email?: string;
   image_url?: string;
-  images?: string[];
+  images?: ProfileImage[];
   services: string[];
   location: string;
   gender?: string;
@@ -72,8 +89,7 @@ export default function ProviderProfilePage() {
         const userData = {
           ...data.data,
           full_name: data.data.full_name || `${data.data.first_name || ''} ${data.data.last_name || ''}`.trim(),
-          images: Array.isArray(data.data.images) ? data.data.images : 
-                 (data.data.image_url ? [data.data.image_url] : []),
+          images: toProfileImages(data.data.images, data.data.image_url),
         };
         setProvider(userData);
       } 
@@ -82,8 +98,7 @@ export default function ProviderProfilePage() {
         const userData = {
           ...data.user,
           full_name: data.user.full_name || `${data.user.first_name || ''} ${data.user.last_name || ''}`.trim(),
-          images: Array.isArray(data.user.images) ? data.user.images : 
-                 (data.user.image_url ? [data.user.image_url] : []),
+          images: toProfileImages(data.user.images, data.user.image_url),
         };
         setProvider(userData);
       }
@@ -92,8 +107,7 @@ export default function ProviderProfilePage() {
         const userData = {
           ...data,
           full_name: data.full_name || `${data.first_name || ''} ${data.last_name || ''}`.trim(),
-          images: Array.isArray(data.images) ? data.images : 
-                 (data.image_url ? [data.image_url] : []),
+          images: toProfileImages(data.images, data.image_url),
         };
         setProvider(userData);
       }
@@ -133,7 +147,7 @@ export default function ProviderProfilePage() {
 
   const getImageSrc = (index: number) => {
     if (!provider?.images || provider.images.length === 0) return '';
-    return provider.images[index] || provider.images[0] || '';
+    return (provider.images[index] || provider.images[0]).full;
   };
 
   if (loading) {
@@ -276,7 +290,7 @@ export default function ProviderProfilePage() {
                   <div className="grid grid-cols-4 sm:grid-cols-6 md:grid-cols-8 gap-2">
                     {allImages.map((image, index) => (
                       <button
-                          key={index}
+                          key={image.id || index}
                           onClick={() => setSelectedImageIndex(index)}
                           className={`rounded-lg overflow-hidden border-2 w-24 h-24 ${
                             selectedImageIndex === index 
@@ -285,8 +299,8 @@ export default function ProviderProfilePage() {
                           }`}
                         >
                           <img
-                            src={image}
-                            alt={`${provider.full_name} - Photo ${index + 1}`}
+                            src={image.thumbnail}
+                            alt={image.caption || `${provider.full_name} - Photo ${index + 1}`}
                             className="object-cover w-full h-full"
                           />
                         </button>
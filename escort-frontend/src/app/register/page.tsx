@@ -1,6 +1,6 @@
 'use client';
 
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import { useRouter } from 'next/navigation';
 import Link from 'next/link';
 
@@ -19,6 +19,19 @@ const serviceOptions = [
   'Role Play'
 ];
 
+interface LocationOption {
+  id: string;
+  label: string;
+  level: number;
+}
+
+// Latest date of birth of someone who is 18 today, as YYYY-MM-DD
+const latestDateOfBirth = (): string => {
+  const date = new Date();
+  date.setFullYear(date.getFullYear() - 18);
+  return date.toISOString().slice(0, 10);
+};
+
 export default function RegisterPage() {
   const router = useRouter();
   const [loading, setLoading] = useState(false);
@@ -33,10 +46,11 @@ export default function RegisterPage() {
     confirmPassword: '',
     gender: '',
     sexualOrientation: '',
-    age: '',
+    dateOfBirth: '',
     nationality: '',
-    location: '',
+    locationId: '',
   });
+  const [locations, setLocations] = useState<LocationOption[]>([]);
   const [selectedServices, setSelectedServices] = useState<string[]>([]);
   const [customService, setCustomService] = useState('');
 
@@ -44,6 +58,18 @@ export default function RegisterPage() {
   const orientationOptions = ['Straight', 'Gay', 'Lesbian', 'Bisexual', 'Pansexual', 'Asexual'];
   const nationalityOptions = ['Kenyan', 'Ugandan', 'Tanzanian', 'Rwandan', 'Burundian', 'Other'];
 
+  // Profiles must be in a place of the location taxonomy
+  useEffect(() => {
+    fetch(`${process.env.NEXT_PUBLIC_API_URL}/locations`)
+      .then(response => response.json())
+      .then(data => {
+        if (data.success && Array.isArray(data.data)) {
+          setLocations(data.data);
+        }
+      })
+      .catch(err => console.error('Failed to load locations:', err));
+  }, []);
+
   const handleChange = (e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement>) => {
     const { name, value } = e.target;
     setFormData(prev => ({ ...prev, [name]: value }));
@@ -79,7 +105,7 @@ export default function RegisterPage() {
 
   const validateForm = (): string | null => {
     // Required fields
-    const requiredFields = ['firstName', 'lastName', 'email', 'phoneNo', 'password', 'confirmPassword', 'gender'];
+    const requiredFields = ['firstName', 'lastName', 'email', 'phoneNo', 'password', 'confirmPassword', 'gender', 'dateOfBirth', 'locationId'];
     for (const field of requiredFields) {
       if (!formData[field as keyof typeof formData]?.trim()) {
         return `${field.replace(/([A-Z])/g, ' $1').toLowerCase()} is required`;
@@ -113,12 +139,9 @@ export default function RegisterPage() {
       return 'Passwords do not match';
     }
 
-    // Age validation
-    if (formData.age) {
-      const age = parseInt(formData.age);
-      if (isNaN(age) || age < 18 || age > 100) {
-        return 'Age must be between 18 and 100';
-      }
+    // Age validation; YYYY-MM-DD strings compare like dates
+    if (formData.dateOfBirth > latestDateOfBirth()) {
+      return 'You must be at least 18 years old to register';
     }
 
     return null;
@@ -170,9 +193,9 @@ export default function RegisterPage() {
           password: formData.password,
           gender: formData.gender,
           sexualOrientation: formData.sexualOrientation || 'Straight',
-          age: formData.age ? parseInt(formData.age) : 25,
+          dateOfBirth: formData.dateOfBirth,
           nationality: formData.nationality || 'Kenyan',
-          location: formData.location || 'Nairobi',
+          locationId: formData.locationId,
           services: selectedServices, // Add services to the registration data
         }),
       });
@@ -193,9 +216,9 @@ export default function RegisterPage() {
           confirmPassword: '',
           gender: '',
           sexualOrientation: '',
-          age: '',
+          dateOfBirth: '',
           nationality: '',
-          location: '',
+          locationId: '',
         });
         setSelectedServices([]);
 
@@ -375,19 +398,17 @@ export default function RegisterPage() {
                 </div>
 
                 <div>
-                  <label htmlFor="age" className="block text-sm font-medium text-gray-700 mb-2">
-                    Age
+                  <label htmlFor="dateOfBirth" className="block text-sm font-medium text-gray-700 mb-2">
+                    Date of Birth *
                   </label>
                   <input
-                    id="age"
-                    name="age"
-                    type="number"
-                    min="18"
-                    max="100"
-                    value={formData.age}
+                    id="dateOfBirth"
+                    name="dateOfBirth"
+                    type="date"
+                    max={latestDateOfBirth()}
+                    value={formData.dateOfBirth}
                     onChange={handleChange}
                     className="w-full px-4 py-3 border border-gray-300 rounded-lg focus:ring-2 focus:ring-purple-500 focus:border-transparent outline-none transition text-gray-900 placeholder:text-gray-500"
-                    placeholder="25"
                   />
                 </div>
 
@@ -411,18 +432,21 @@ export default function RegisterPage() {
               </div>
 
               <div className="mt-6">
-                <label htmlFor="location" className="block text-sm font-medium text-gray-700 mb-2">
-                  Location/City
+                <label htmlFor="locationId" className="block text-sm font-medium text-gray-700 mb-2">
+                  Location/City *
                 </label>
-                <input
-                  id="location"
-                  name="location"
-                  type="text"
-                  value={formData.location}
+                <select
+                  id="locationId"
+                  name="locationId"
+                  value={formData.locationId}
                   onChange={handleChange}
-                  className="w-full px-4 py-3 border border-gray-300 rounded-lg focus:ring-2 focus:ring-purple-500 focus:border-transparent outline-none transition text-gray-900 placeholder:text-gray-500"
-                  placeholder="Nairobi, Westlands"
-                />
+                  className="w-full px-4 py-3 border border-gray-300 rounded-lg focus:ring-2 focus:ring-purple-500 focus:border-transparent outline-none transition text-gray-900"
+                >
+                  <option value="">Select Location</option>
+                  {locations.map(location => (
+                    <option key={location.id} value={location.id}>{location.label}</option>
+                  ))}
+                </select>
               </div>
             </div>
 
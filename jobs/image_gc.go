@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// orphanGracePeriod protects objects whose upload finished but whose user
+// document hasn't been updated yet
+const orphanGracePeriod = 24 * time.Hour
+
+// ImageGCReport summarises one garbage collection run
+type ImageGCReport struct {
+	Scanned    int      `json:"scanned"`
+	Referenced int      `json:"referenced"`
+	Orphaned   []string `json:"orphaned"`
+	Deleted    int      `json:"deleted"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+// StartImageGC runs CollectOrphanedImages every interval in the background
+func StartImageGC(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+			report, err := CollectOrphanedImages(ctx, false)
+			cancel()
+
+			if err != nil {
+				log.Printf("⚠️ Image cleanup failed: %v", err)
+				continue
+			}
+			fmt.Printf("🧹 Image cleanup: scanned %d, deleted %d orphaned objects\n", report.Scanned, report.Deleted)
+		}
+	}()
+}
+
+// CollectOrphanedImages deletes photos in the profile bucket, and unwatermarked
+// originals in the private bucket, that no user references
+func CollectOrphanedImages(ctx context.Context, dryRun bool) (*ImageGCReport, error) {
+	store, err := services.NewObjectStore(services.ProfileBucket())
+	if err != nil {
+		return nil, err
+	}
+	privateStore, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		return nil, err
+	}
+
+	// List first, so anything uploaded after this point is never considered
+	objects, err := store.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	originals, err := privateStore.List(ctx, services.ProfileOriginalsPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list originals: %w", err)
+	}
+
+	referenced, err := referencedImageKeys(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImageGCReport{DryRun: dryRun, Orphaned: []string{}}
+	cutoff := time.Now().Add(-orphanGracePeriod)
+
+	sweepOrphans(ctx, store, objects, referenced.has, cutoff, report)
+	// Originals mirror the photo folders under their own prefix
+	sweepOrphans(ctx, privateStore, originals, func(key string) bool {
+		return referenced.has(strings.TrimPrefix(key, services.ProfileOriginalsPrefix))
+	}, cutoff, report)
+
+	return report, nil
+}
+
+// sweepOrphans deletes the unreferenced objects older than cutoff
+func sweepOrphans(ctx context.Context, store services.ObjectStore, objects []services.ObjectInfo, isReferenced func(string) bool, cutoff time.Time, report *ImageGCReport) {
+	report.Scanned += len(objects)
+
+	for _, object := range objects {
+		if isReferenced(object.Key) {
+			report.Referenced++
+			continue
+		}
+		if object.UpdatedAt.After(cutoff) {
+			continue
+		}
+
+		report.Orphaned = append(report.Orphaned, object.Key)
+		if report.DryRun {
+			continue
+		}
+		if err := store.Delete(ctx, object.Key); err != nil {
+			log.Printf("⚠️ Could not delete orphaned object %s: %v", object.Key, err)
+			continue
+		}
+		report.Deleted++
+	}
+}
+
+// referencedImages is the set of stored objects some user still points at
+type referencedImages struct {
+	keys    map[string]bool
+	folders map[string]bool
+}
+
+// has reports whether key is referenced directly or lives under a photo's folder
+func (r *referencedImages) has(key string) bool {
+	if r.keys[key] {
+		return true
+	}
+	slash := strings.LastIndex(key, "/")
+	return slash >= 0 && r.folders[key[:slash+1]]
+}
+
+// referencedImageKeys collects the storage keys of every photo referenced by a user
+func referencedImageKeys(ctx context.Context, store services.ObjectStore) (*referencedImages, error) {
+	findOptions := options.Find().SetProjection(bson.M{"images": 1, "image_url": 1})
+
+	cursor, err := database.UserCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	referenced := &referencedImages{keys: map[string]bool{}, folders: map[string]bool{}}
+	for cursor.Next(ctx) {
+		var user struct {
+			Images   []models.ProfileImage `bson:"images"`
+			ImageURL string                `bson:"image_url"`
+		}
+		// A user we can't read must not make their photos look orphaned
+		if err := cursor.Decode(&user); err != nil {
+			return nil, fmt.Errorf("failed to read user: %w", err)
+		}
+
+		urls := []string{user.ImageURL}
+		for _, image := range user.Images {
+			urls = append(urls, image.URLs()...)
+			if strings.HasSuffix(image.Path, "/") {
+				referenced.folders[image.Path] = true
+			}
+		}
+
+		for _, url := range urls {
+			if key, ok := store.KeyFromURL(url); ok {
+				referenced.keys[key] = true
+			}
+		}
+	}
+
+	// A failed read must not make every object look orphaned
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	return referenced, nil
+}
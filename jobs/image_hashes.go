@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"escort/controllers"
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxHashSourceBytes caps how much of a stored photo is read to hash it
+const maxHashSourceBytes = 20 << 20
+
+// BackfillImageHashes computes the perceptual hash of photos uploaded before
+// hashing existed and indexes every hash for duplicate lookups, so duplicate
+// detection also covers older photos
+func BackfillImageHashes() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	indexImageHashes(ctx)
+
+	store, err := services.NewObjectStore(services.ProfileBucket())
+	if err != nil {
+		log.Printf("⚠️ Warning: Image hash backfill skipped: %v", err)
+		return
+	}
+
+	findOptions := options.Find().SetProjection(bson.M{"images": 1})
+	cursor, err := database.UserCollection.Find(ctx, bson.M{
+		"images": bson.M{"$elemMatch": bson.M{"id": bson.M{"$exists": true}, "phash": bson.M{"$exists": false}}},
+	}, findOptions)
+	if err != nil {
+		log.Printf("⚠️ Warning: Image hash backfill failed: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	hashed := 0
+	for cursor.Next(ctx) {
+		var user struct {
+			ID     primitive.ObjectID    `bson:"_id"`
+			Images []models.ProfileImage `bson:"images"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			continue
+		}
+
+		for _, image := range user.Images {
+			if image.Hash != "" || image.ID.IsZero() {
+				continue
+			}
+
+			hash, err := hashStoredImage(ctx, store, image)
+			if err != nil {
+				log.Printf("⚠️ Could not hash image %s of user %s: %v", image.ID.Hex(), user.ID.Hex(), err)
+				continue
+			}
+
+			_, err = database.UserCollection.UpdateOne(ctx,
+				bson.M{"_id": user.ID},
+				bson.M{"$set": bson.M{"images.$[img].phash": hash}},
+				options.Update().SetArrayFilters(options.ArrayFilters{
+					Filters: []interface{}{bson.M{"img.id": image.ID}},
+				}),
+			)
+			if err != nil {
+				continue
+			}
+			hashed++
+
+			image.Hash = hash
+			if err := controllers.RecordImageHashes(ctx, user.ID, []models.ProfileImage{image}); err != nil {
+				log.Printf("⚠️ Could not index hash of image %s: %v", image.ID.Hex(), err)
+			}
+		}
+	}
+
+	if hashed > 0 {
+		fmt.Printf("🔍 Computed perceptual hashes of %d existing photos\n", hashed)
+	}
+}
+
+// indexImageHashes adds the hashes already stored on photos to the hash index.
+// Indexing is idempotent, so photos hashed before the index existed are caught up.
+func indexImageHashes(ctx context.Context) {
+	findOptions := options.Find().SetProjection(bson.M{"images.id": 1, "images.phash": 1})
+	cursor, err := database.UserCollection.Find(ctx, bson.M{"images.phash": bson.M{"$exists": true}}, findOptions)
+	if err != nil {
+		log.Printf("⚠️ Warning: Image hash indexing failed: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user struct {
+			ID     primitive.ObjectID    `bson:"_id"`
+			Images []models.ProfileImage `bson:"images"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			continue
+		}
+		if err := controllers.RecordImageHashes(ctx, user.ID, user.Images); err != nil {
+			log.Printf("⚠️ Could not index photo hashes of user %s: %v", user.ID.Hex(), err)
+		}
+	}
+}
+
+// hashStoredImage downloads the smallest rendition of image and hashes it
+func hashStoredImage(ctx context.Context, store services.ObjectStore, image models.ProfileImage) (string, error) {
+	key, ok := store.KeyFromURL(image.Thumbnail)
+	if !ok {
+		return "", fmt.Errorf("photo is not in the profile bucket")
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxHashSourceBytes))
+	if err != nil {
+		return "", err
+	}
+
+	img, _, err := services.DecodeImage(data)
+	if err != nil {
+		return "", err
+	}
+	return services.FormatPerceptualHash(services.PerceptualHash(services.ApplyOrientation(img, services.JPEGOrientation(data)))), nil
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"escort/controllers"
+)
+
+// StartSavedSearchMatcher notifies clients of new matches for their saved
+// searches every interval in the background
+func StartSavedSearchMatcher(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			err := controllers.MatchSavedSearches(ctx)
+			cancel()
+
+			if err != nil {
+				log.Printf("⚠️ Saved search matching failed: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"escort/database"
+	"escort/models"
+	"escort/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StartUploadCleanup removes expired resumable uploads every interval in the background
+func StartUploadCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			removed, err := CleanupExpiredUploads(ctx)
+			cancel()
+
+			if err != nil {
+				log.Printf("⚠️ Upload cleanup failed: %v", err)
+				continue
+			}
+			if removed > 0 {
+				fmt.Printf("🧹 Upload cleanup: removed %d expired uploads\n", removed)
+			}
+		}
+	}()
+}
+
+// CleanupExpiredUploads deletes expired upload sessions with their chunks, and
+// chunks that no session refers to
+func CleanupExpiredUploads(ctx context.Context) (int, error) {
+	store, err := services.NewObjectStore(services.PrivateBucket())
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	cursor, err := database.UploadSessionCollection.Find(ctx,
+		bson.M{"expires_at": bson.M{"$lt": now}},
+		options.Find().SetProjection(bson.M{"chunks": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	removed := 0
+	for cursor.Next(ctx) {
+		var session models.UploadSession
+		if err := cursor.Decode(&session); err != nil {
+			continue
+		}
+		for _, key := range session.Chunks {
+			store.Delete(ctx, key)
+		}
+		if _, err := database.UploadSessionCollection.DeleteOne(ctx, bson.M{"_id": session.ID}); err == nil {
+			removed++
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return removed, err
+	}
+
+	// Chunks left behind by failed requests: uploads/<userID>/<sessionID>/<offset>-<random>
+	chunks, err := store.List(ctx, services.UploadChunkPrefix)
+	if err != nil {
+		return removed, err
+	}
+	// Keys of the chunks each session refers to, nil when the session is gone
+	checked := map[string]map[string]bool{}
+	for _, chunk := range chunks {
+		if chunk.UpdatedAt.After(now.Add(-orphanGracePeriod)) {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(chunk.Key, services.UploadChunkPrefix), "/")
+		if len(parts) != 3 {
+			continue
+		}
+
+		sessionID, err := primitive.ObjectIDFromHex(parts[1])
+		if err != nil {
+			continue
+		}
+		referenced, seen := checked[parts[1]]
+		if !seen {
+			var session models.UploadSession
+			err := database.UploadSessionCollection.FindOne(ctx, bson.M{"_id": sessionID},
+				options.FindOne().SetProjection(bson.M{"chunks": 1}),
+			).Decode(&session)
+			if err != nil && err != mongo.ErrNoDocuments {
+				continue
+			}
+			if err == nil {
+				referenced = map[string]bool{}
+				for _, key := range session.Chunks {
+					referenced[key] = true
+				}
+			}
+			checked[parts[1]] = referenced
+		}
+		if !referenced[chunk.Key] {
+			store.Delete(ctx, chunk.Key)
+		}
+	}
+
+	return removed, nil
+}
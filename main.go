@@ -3,10 +3,14 @@ package main
 import (
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	"escort/controllers"
 	"escort/database"
+	"escort/jobs"
 	"escort/routes"
+	"escort/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -32,18 +36,36 @@ func main() {
 	// CORS middleware
 	router.Use(CORSMiddleware())
 
-	// Serve static files
-	router.Static("/uploads", "./uploads")
+	// Serve the public buckets of the local object store. Private objects
+	// (photo originals, verification files) are only reachable through signed URLs.
+	for _, bucket := range services.PublicBuckets() {
+		if bucket != services.PrivateBucket() {
+			router.Static("/uploads/"+bucket, filepath.Join(services.LocalStorageDir(), bucket))
+		}
+	}
+	router.GET("/storage/signed/:bucket/*key", controllers.ServeSignedObject)
 
 	// Initialize subscription controller
 	subscriptionController := controllers.NewSubscriptionController(db)
 
 	// Initialize blog controller
 	controllers.InitBlogCollection(database.GetClient())
+	controllers.InitMediaCollection(database.GetClient())
+	controllers.MigrateProfileImages()
+	go controllers.MigrateUserLocations()
+	go controllers.MigrateUserServices()
+	go controllers.SyncSubscriptionRanks()
+	go controllers.MigrateDeviceLocations()
 
 	// Setup all routes
 	setupRoutes(router, subscriptionController)
 
+	// Background jobs
+	jobs.StartImageGC(24 * time.Hour)
+	go jobs.BackfillImageHashes()
+	jobs.StartUploadCleanup(time.Hour)
+	jobs.StartSavedSearchMatcher(10 * time.Minute)
+
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -82,7 +104,9 @@ func setupRoutes(router *gin.Engine, subscriptionController *controllers.Subscri
 	routes.AdminRoutes(router)
 	routes.SubscriptionRoutes(router, subscriptionController)
 	routes.BlogRoutes(router) // Add blog routes
+	routes.MediaRoutes(router)
 	routes.TelegramRoutes(router)
+	routes.FeedRoutes(router)
 }
 
 
@@ -104,8 +128,10 @@ func CORSMiddleware() gin.HandlerFunc {
 		}
 
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, Origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, Origin, Cache-Control, X-Requested-With, Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH, HEAD")
+		// Let browser tus clients read the resumable upload headers
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Location, Tus-Resumable, Tus-Version, Tus-Extension, Tus-Max-Size, Upload-Offset, Upload-Length")
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"escort/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// activityInterval is how often a user's last_active_at is written at most
+const activityInterval = 5 * time.Minute
+
+var lastActivity sync.Map // user ID -> time.Time of the last write
+
+// RecordActivity updates the user's last_active_at (used by the "recently active"
+// listing sort) without writing more than once per activityInterval
+func RecordActivity(userID string) {
+	now := time.Now()
+	if last, ok := lastActivity.Load(userID); ok && now.Sub(last.(time.Time)) < activityInterval {
+		return
+	}
+	lastActivity.Store(userID, now)
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		database.UserCollection.UpdateOne(ctx, bson.M{"_id": userObjID}, bson.M{"$set": bson.M{"last_active_at": now}})
+	}()
+}
@@ -131,6 +131,7 @@ func RequireAuth() gin.HandlerFunc {
 			// Set user info in context for use in controllers
 			c.Set("userID", userID)
 			c.Set("userRole", role)
+			RecordActivity(userID)
 			c.Next()
 			return
 		}
@@ -143,3 +144,18 @@ func RequireAuth() gin.HandlerFunc {
 		c.Abort()
 	}
 }
+
+// RequireRole lets through users of role only; it must follow RequireAuth
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("userRole") != role {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "This endpoint is only available to " + role + " accounts",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
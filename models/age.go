@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinimumAge is the youngest a provider may be to register
+const MinimumAge = 18
+
+// DateOfBirthLayout is the format dates of birth are sent in
+const DateOfBirthLayout = "2006-01-02"
+
+// AgeOn returns how old someone born on dob is on day now
+func AgeOn(dob, now time.Time) int {
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
+
+// ParseDateOfBirth parses a YYYY-MM-DD date of birth and checks the person is an adult
+func ParseDateOfBirth(value string, now time.Time) (time.Time, error) {
+	dob, err := time.Parse(DateOfBirthLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date of birth must be in YYYY-MM-DD format")
+	}
+	if dob.After(now) {
+		return time.Time{}, fmt.Errorf("date of birth cannot be in the future")
+	}
+	if age := AgeOn(dob, now); age < MinimumAge {
+		return time.Time{}, fmt.Errorf("you must be at least %d years old to register", MinimumAge)
+	} else if age > 120 {
+		return time.Time{}, fmt.Errorf("invalid date of birth")
+	}
+	return dob, nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAgeOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		dob, now time.Time
+		want     int
+	}{
+		{"day of birth", date(2000, 6, 15), date(2000, 6, 15), 0},
+		{"day before birthday", date(2000, 6, 15), date(2026, 6, 14), 25},
+		{"on birthday", date(2000, 6, 15), date(2026, 6, 15), 26},
+		{"month before birthday", date(2000, 6, 15), date(2026, 5, 30), 25},
+		{"month after birthday", date(2000, 6, 15), date(2026, 7, 1), 26},
+		{"new year's eve", date(2000, 1, 1), date(2025, 12, 31), 25},
+		{"leap day before the 1st of March", date(2008, 2, 29), date(2026, 2, 28), 17},
+		{"leap day from the 1st of March", date(2008, 2, 29), date(2026, 3, 1), 18},
+		{"leap day in a leap year", date(2008, 2, 29), date(2028, 2, 29), 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AgeOn(tt.dob, tt.now); got != tt.want {
+				t.Errorf("AgeOn(%s, %s) = %d, want %d", tt.dob.Format(DateOfBirthLayout), tt.now.Format(DateOfBirthLayout), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateOfBirth(t *testing.T) {
+	now := date(2026, 10, 18)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2008-10-18", date(2008, 10, 18), false}, // Turns 18 today
+		{"2008-10-19", time.Time{}, true},         // Turns 18 tomorrow
+		{"1990-01-31", date(1990, 1, 31), false},
+		{"1906-10-18", date(1906, 10, 18), false}, // 120 is the oldest accepted
+		{"1905-10-18", time.Time{}, true},
+		{"2027-01-01", time.Time{}, true}, // In the future
+		{"2026-10-18", time.Time{}, true},
+		{"18/10/1990", time.Time{}, true},
+		{"1990-02-30", time.Time{}, true},
+		{"1990-1-5", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateOfBirth(tt.value, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDateOfBirth(%q) = %s, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDateOfBirth(%q): %v", tt.value, err)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("ParseDateOfBirth(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
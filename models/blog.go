@@ -7,16 +7,22 @@ import (
 )
 
 type Blog struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title       string             `bson:"title" json:"title" binding:"required"`
-	Slug        string             `bson:"slug" json:"slug" binding:"required"`
-	Description string             `bson:"description" json:"description" binding:"required"`
-	Content     string             `bson:"content" json:"content" binding:"required"`
-	Author      string             `bson:"author" json:"author" binding:"required"`
-	Category    string             `bson:"category" json:"category" binding:"required"`
-	Image       string             `bson:"image" json:"image"`
-	Keywords    []string           `bson:"keywords" json:"keywords"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
-	Published   bool               `bson:"published" json:"published"`
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title       string              `bson:"title" json:"title" binding:"required"`
+	Slug        string              `bson:"slug" json:"slug"` // Generated from Title when omitted
+	Description string              `bson:"description" json:"description" binding:"required"`
+	Content     string              `bson:"content" json:"content" binding:"required"`
+	Author      string              `bson:"author" json:"author" binding:"required"`
+	Category    string              `bson:"category" json:"category" binding:"required"`
+	Image       string              `bson:"image" json:"image"`                           // Resolved from ImageID, not client supplied
+	ImageID     *primitive.ObjectID `bson:"image_id,omitempty" json:"image_id,omitempty"` // Media library item used as the cover
+	ImageAlt    string              `bson:"image_alt,omitempty" json:"image_alt,omitempty"`
+	Keywords    []string            `bson:"keywords" json:"keywords"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+	Published   bool                `bson:"published" json:"published"`
+	PublishedAt time.Time           `bson:"published_at,omitempty" json:"published_at,omitempty"` // Set the first time the post is published
+
+	// Slugs this post was previously published under, kept so old links can redirect
+	PreviousSlugs []string `bson:"previous_slugs,omitempty" json:"previous_slugs,omitempty"`
 }
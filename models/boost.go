@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Boost statuses
+const (
+	BoostPending = "pending"
+	BoostActive  = "active"
+	BoostFailed  = "failed"
+)
+
+// BoostOption is a price and duration of "bump to top", set by admins per
+// location. Options without a location apply wherever none are configured.
+type BoostOption struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	LocationID    *primitive.ObjectID `bson:"location_id,omitempty" json:"location_id,omitempty"`
+	LocationLabel string              `bson:"location_label,omitempty" json:"location_label,omitempty"`
+	Amount        float64             `bson:"amount" json:"amount"`
+	DurationHours int                 `bson:"duration_hours" json:"duration_hours"`
+	IsActive      bool                `bson:"is_active" json:"is_active"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Boost is a one-off purchase that puts a profile at the top of listings for a few hours
+type Boost struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	OptionID      primitive.ObjectID  `bson:"option_id" json:"option_id"`
+	LocationID    *primitive.ObjectID `bson:"location_id,omitempty" json:"location_id,omitempty"`
+	Amount        float64             `bson:"amount" json:"amount"`
+	DurationHours int                 `bson:"duration_hours" json:"duration_hours"`
+	Status        string              `bson:"status" json:"status"`
+
+	// MPESA payment, matched to the boost by the checkout ID of the STK push
+	CheckoutID    string `bson:"checkout_id,omitempty" json:"checkout_id,omitempty"`
+	PhoneUsed     string `bson:"phone_used,omitempty" json:"phone_used,omitempty"`
+	MpesaReceipt  string `bson:"mpesa_receipt,omitempty" json:"mpesa_receipt,omitempty"`
+	FailureReason string `bson:"failure_reason,omitempty" json:"failure_reason,omitempty"`
+
+	// Set on payment; a boost bought while another runs starts when it ends
+	StartsAt *time.Time `bson:"starts_at,omitempty" json:"starts_at,omitempty"`
+	EndsAt   *time.Time `bson:"ends_at,omitempty" json:"ends_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
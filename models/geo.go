@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// GeoPoint is a GeoJSON point as stored for 2dsphere queries.
+// Coordinates are [longitude, latitude] as GeoJSON requires.
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// Sources of a profile's geo location
+const (
+	GeoSourceArea   = "area"   // Centre of an area the provider picked
+	GeoSourceDevice = "device" // Coordinates reported by the provider's device
+)
+
+const earthRadiusMeters = 6371000
+
+// NewGeoPoint returns the point at lat, lng after checking the coordinates are valid
+func NewGeoPoint(lat, lng float64) (*GeoPoint, error) {
+	if math.IsNaN(lat) || math.IsNaN(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("invalid coordinates")
+	}
+	return &GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}, nil
+}
+
+// Lat is the point's latitude
+func (p GeoPoint) Lat() float64 { return p.Coordinates[1] }
+
+// Lng is the point's longitude
+func (p GeoPoint) Lng() float64 { return p.Coordinates[0] }
+
+// Fuzz moves the point in a random direction by up to maxMeters, so a provider's
+// exact position can't be recovered from distances shown to clients
+func (p GeoPoint) Fuzz(maxMeters float64) GeoPoint {
+	// Square root keeps the offsets evenly spread over the disc
+	distance := maxMeters * math.Sqrt(rand.Float64())
+	bearing := rand.Float64() * 2 * math.Pi
+
+	lat := p.Lat() + (distance*math.Cos(bearing)/earthRadiusMeters)*180/math.Pi
+	lng := p.Lng() + (distance*math.Sin(bearing)/(earthRadiusMeters*math.Cos(p.Lat()*math.Pi/180)))*180/math.Pi
+
+	return GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}
+}
+
+// Snap moves the point to the nearest corner of a grid of stepDegrees. Snapped
+// points don't change when snapped again, and repeated reports from one place
+// all land on the same corner, so they can't be averaged back to it.
+func (p GeoPoint) Snap(stepDegrees float64) GeoPoint {
+	snap := func(value float64) float64 {
+		return math.Round(value/stepDegrees) * stepDegrees
+	}
+	return GeoPoint{Type: "Point", Coordinates: []float64{snap(p.Lng()), snap(p.Lat())}}
+}
+
+// DistanceLabel describes a distance in meters loosely enough not to pinpoint anyone
+func DistanceLabel(meters float64) string {
+	km := meters / 1000
+	switch {
+	case km < 1:
+		return "Less than 1 km away"
+	case km < 10:
+		return fmt.Sprintf("About %d km away", int(math.Round(km)))
+	default:
+		return fmt.Sprintf("About %d km away", int(math.Round(km/5))*5)
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImageHash indexes the perceptual hash of one profile photo for duplicate
+// detection. Bands are runs of the hash's bits, so near-identical photos can be
+// found through an index instead of comparing every stored hash.
+type ImageHash struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	ImageID   primitive.ObjectID `bson:"image_id"`
+	Hash      string             `bson:"phash"` // Hex, as on the photo
+	Bands     []string           `bson:"bands"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Location is a county, town or area of the location taxonomy
+type Location struct {
+	ID        primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name      string               `bson:"name" json:"name"`
+	Slug      string               `bson:"slug" json:"slug"`
+	Type      string               `bson:"type" json:"type"`   // "county", "town" or "area"
+	Level     int                  `bson:"level" json:"level"` // 0 county, 1 town, 2 area
+	Label     string               `bson:"label" json:"label"` // Display name, e.g. "Westlands, Nairobi"
+	Code      int                  `bson:"code,omitempty" json:"code,omitempty"`
+	ParentID  *primitive.ObjectID  `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	Ancestors []primitive.ObjectID `bson:"ancestors" json:"ancestors"`
+	Path      []primitive.ObjectID `bson:"path" json:"-"` // Ancestors and the location itself
+	Aliases   []string             `bson:"aliases,omitempty" json:"aliases,omitempty"`
+	Center    *GeoPoint            `bson:"center,omitempty" json:"center,omitempty"`
+	IsActive  bool                 `bson:"is_active" json:"is_active"`
+	CreatedAt time.Time            `bson:"created_at" json:"created_at"`
+}
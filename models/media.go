@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MediaItem - An image in the blog media library
+type MediaItem struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL          string             `bson:"url" json:"url"`
+	ThumbnailURL string             `bson:"thumbnail_url" json:"thumbnail_url"`
+	StoragePath  string             `bson:"storage_path" json:"-"` // Folder holding the stored renditions
+	AltText      string             `bson:"alt_text" json:"alt_text"`
+	OriginalName string             `bson:"original_name" json:"original_name"`
+	ContentType  string             `bson:"content_type" json:"content_type"`
+	Width        int                `bson:"width" json:"width"`
+	Height       int                `bson:"height" json:"height"`
+	Size         int64              `bson:"size" json:"size"` // Bytes of the full-size rendition
+	UploadedBy   string             `bson:"uploaded_by" json:"uploaded_by"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
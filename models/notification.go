@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification is a message shown in a user's in-app inbox
+type Notification struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID     `bson:"user_id" json:"user_id"`
+	Type      string                 `bson:"type" json:"type"` // e.g. "photo_rejected"
+	Title     string                 `bson:"title" json:"title"`
+	Message   string                 `bson:"message" json:"message"`
+	Data      map[string]interface{} `bson:"data,omitempty" json:"data,omitempty"`
+	Read      bool                   `bson:"read" json:"read"`
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProfileImage is one uploaded profile photo with its re-encoded renditions
+type ProfileImage struct {
+	ID        primitive.ObjectID `bson:"id" json:"id"`
+	Thumbnail string             `bson:"thumbnail" json:"thumbnail"`
+	Card      string             `bson:"card" json:"card"`
+	Full      string             `bson:"full" json:"full"`
+	Width     int                `bson:"width" json:"width"`
+	Height    int                `bson:"height" json:"height"`
+	Caption   string             `bson:"caption,omitempty" json:"caption,omitempty"`
+	Path      string             `bson:"path,omitempty" json:"-"` // Storage key prefix of the renditions
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+
+	// Unwatermarked copy in the private bucket, only shown to moderators
+	OriginalKey string `bson:"original_key,omitempty" json:"-"`
+
+	// Moderation; photos stored before moderation existed have no status and count as approved
+	Status          string     `bson:"status,omitempty" json:"status,omitempty"` // "pending", "approved", "rejected"
+	RejectionReason string     `bson:"rejection_reason,omitempty" json:"rejection_reason,omitempty"`
+	ModeratedAt     *time.Time `bson:"moderated_at,omitempty" json:"moderated_at,omitempty"`
+	ModeratedBy     string     `bson:"moderated_by,omitempty" json:"-"`
+
+	// Duplicate detection; kept out of JSON so owners and visitors never see other accounts
+	Hash       string           `bson:"phash,omitempty" json:"-"` // Hex perceptual hash of the photo
+	Duplicates []ImageDuplicate `bson:"duplicates,omitempty" json:"-"`
+}
+
+// ImageDuplicate is a near-identical photo found on another account
+type ImageDuplicate struct {
+	UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ImageID  primitive.ObjectID `bson:"image_id" json:"image_id"`
+	Distance int                `bson:"distance" json:"distance"` // Differing hash bits, 0 means identical
+}
+
+// Photo moderation statuses
+const (
+	ImageStatusPending  = "pending"
+	ImageStatusApproved = "approved"
+	ImageStatusRejected = "rejected"
+)
+
+// profileImageFields avoids recursing into UnmarshalBSONValue
+type profileImageFields ProfileImage
+
+// UnmarshalBSONValue also accepts the plain URL strings stored before photos
+// were processed; all renditions then point at the original upload
+func (img *ProfileImage) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.String {
+		var url string
+		if err := bson.UnmarshalValue(t, data, &url); err != nil {
+			return err
+		}
+		*img = ProfileImage{Thumbnail: url, Card: url, Full: url}
+		return nil
+	}
+
+	var fields profileImageFields
+	if err := bson.UnmarshalValue(t, data, &fields); err != nil {
+		return err
+	}
+	*img = ProfileImage(fields)
+	return nil
+}
+
+// URLs lists the distinct URLs of every rendition
+func (img ProfileImage) URLs() []string {
+	var urls []string
+	for _, url := range []string{img.Thumbnail, img.Card, img.Full} {
+		if url == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range urls {
+			if existing == url {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// HasURL reports whether url is one of the renditions of img
+func (img ProfileImage) HasURL(url string) bool {
+	return url != "" && (url == img.Thumbnail || url == img.Card || url == img.Full)
+}
+
+// MaxImageCaptionLength limits photo captions
+const MaxImageCaptionLength = 200
+
+// IsApproved reports whether img may be shown publicly
+func (img ProfileImage) IsApproved() bool {
+	return img.Status == ImageStatusApproved || img.Status == ""
+}
+
+// PublicImages keeps only the approved photos, in order
+func PublicImages(images []ProfileImage) []ProfileImage {
+	public := []ProfileImage{}
+	for _, image := range images {
+		if image.IsApproved() {
+			public = append(public, image)
+		}
+	}
+	return public
+}
+
+// CoverImage is the photo shown on listings: the chosen primary photo once
+// approved, otherwise the first approved photo. It is nil when none is approved.
+func CoverImage(images []ProfileImage, primaryID *primitive.ObjectID) *ProfileImage {
+	if primaryID != nil {
+		for i := range images {
+			if images[i].ID == *primaryID && images[i].IsApproved() {
+				return &images[i]
+			}
+		}
+	}
+	for i := range images {
+		if images[i].IsApproved() {
+			return &images[i]
+		}
+	}
+	return nil
+}
+
+// CoverImageURL is the image_url of a user with these photos
+func CoverImageURL(images []ProfileImage, primaryID *primitive.ObjectID) string {
+	if cover := CoverImage(images, primaryID); cover != nil {
+		return cover.Card
+	}
+	return ""
+}
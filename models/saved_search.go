@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// How often a saved search reports new matches
+const (
+	SearchInstant = "instant" // As soon as the matcher finds them
+	SearchDaily   = "daily"   // In one digest a day
+)
+
+// SearchFilters are the listing filters of a saved search, named after the
+// query parameters of GET /search
+type SearchFilters struct {
+	Text        string   `bson:"q,omitempty" json:"q,omitempty"`
+	Location    string   `bson:"location,omitempty" json:"location,omitempty"`
+	Services    []string `bson:"services,omitempty" json:"service,omitempty"`
+	Gender      string   `bson:"gender,omitempty" json:"gender,omitempty"`
+	Nationality string   `bson:"nationality,omitempty" json:"nationality,omitempty"`
+	Orientation string   `bson:"orientation,omitempty" json:"orientation,omitempty"`
+	MinAge      int      `bson:"min_age,omitempty" json:"min_age,omitempty"`
+	MaxAge      int      `bson:"max_age,omitempty" json:"max_age,omitempty"`
+	Verified    bool     `bson:"verified,omitempty" json:"verified,omitempty"`
+}
+
+// SavedSearch is a client's search that notifies them of profiles that start
+// matching it
+type SavedSearch struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name      string             `bson:"name" json:"name"`
+	Filters   SearchFilters      `bson:"filters" json:"filters"`
+	Channel   string             `bson:"channel" json:"channel"`     // "in_app", "email" or "telegram"
+	Frequency string             `bson:"frequency" json:"frequency"` // SearchInstant or SearchDaily
+	IsActive  bool               `bson:"is_active" json:"is_active"`
+
+	// Profiles that matched at the last run; any other match is new
+	SeenIDs []primitive.ObjectID `bson:"seen_ids" json:"-"`
+	// New matches not notified yet: waiting for the daily digest, or for a retry
+	// after a failed delivery
+	PendingIDs []primitive.ObjectID `bson:"pending_ids,omitempty" json:"-"`
+
+	LastMatchedAt *time.Time `bson:"last_matched_at,omitempty" json:"last_matched_at,omitempty"` // Last time new matches were found
+	LastDigestAt  time.Time  `bson:"last_digest_at" json:"last_digest_at"`
+	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Service is an entry of the services catalogue providers pick from.
+// Profiles store the service names, so renaming one updates every profile.
+type Service struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Slug      string             `bson:"slug" json:"slug"`
+	Category  string             `bson:"category" json:"category"`
+	Aliases   []string           `bson:"aliases,omitempty" json:"aliases,omitempty"`
+	Keys      []string           `bson:"keys" json:"-"` // Lowercased slug, name and aliases for lookups
+	IsActive  bool               `bson:"is_active" json:"is_active"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
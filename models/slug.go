@@ -0,0 +1,25 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify turns a name or title into a lowercase, hyphen separated URL slug
+// ("Wang'uru" -> "wanguru", "Nairobi CBD" -> "nairobi-cbd")
+func Slugify(name string) string {
+	var b strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '\'' || r == '’':
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			lastHyphen = false
+		case !lastHyphen:
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
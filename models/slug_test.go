@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Nairobi", "nairobi"},
+		{"Nairobi CBD", "nairobi-cbd"},
+		{"Wang'uru", "wanguru"},
+		{"Murang’a", "muranga"},
+		{"Full Body Massage", "full-body-massage"},
+		{"  Video -- Call!  ", "video-call"},
+		{"Top 10 spas in 2026", "top-10-spas-in-2026"},
+		{"Café Mombasa", "caf-mombasa"},
+		{"!!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.name); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
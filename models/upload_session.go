@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadSession tracks a resumable photo upload. Each PATCH is stored as its own
+// chunk object so an interrupted upload resumes from the last completed chunk.
+type UploadSession struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Filename  string              `bson:"filename,omitempty" json:"filename,omitempty"`
+	Size      int64               `bson:"size" json:"size"`     // Upload-Length
+	Offset    int64               `bson:"offset" json:"offset"` // Bytes received so far
+	Chunks    []string            `bson:"chunks" json:"-"`      // Storage keys of the received chunks, in order
+	Status    string              `bson:"status" json:"status"` // "uploading", "processing", "complete", "failed"
+	Error     string              `bson:"error,omitempty" json:"error,omitempty"`
+	ImageID   *primitive.ObjectID `bson:"image_id,omitempty" json:"image_id,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+	ExpiresAt time.Time           `bson:"expires_at" json:"expires_at"`
+}
+
+// Upload session statuses
+const (
+	UploadStatusUploading  = "uploading"
+	UploadStatusProcessing = "processing"
+	UploadStatusComplete   = "complete"
+	UploadStatusFailed     = "failed"
+)
@@ -21,16 +21,65 @@ type User struct {
 	Age               int    `bson:"age" json:"age,omitempty"`
 	Nationality       string `bson:"nationality" json:"nationality,omitempty"`
 
-	Services           []string  `bson:"services" json:"services"`
-	Images             []string  `json:"images,omitempty" bson:"images,omitempty"`
-	ImageUrl           string    `bson:"image_url" json:"image_url"`
-	IsActive           bool      `bson:"is_active" json:"is_active"`
-	Role               string    `bson:"role" json:"role"` // "user" or "admin"
-	HasSubscription    bool      `bson:"has_subscription" json:"has_subscription"`
-	SubscriptionExpiry time.Time `bson:"subscription_expiry,omitempty" json:"subscription_expiry,omitempty"`
-	LastPaymentDate    time.Time `bson:"last_payment_date,omitempty" json:"last_payment_date,omitempty"`
-	CreatedAt          time.Time `bson:"created_at" json:"created_at,omitempty"`
-	UpdatedAt          time.Time `bson:"updated_at" json:"updated_at,omitempty"`
+	Services           []string       `bson:"services" json:"services"`
+	Images             []ProfileImage `json:"images,omitempty" bson:"images,omitempty"`
+	ImageUrl           string         `bson:"image_url" json:"image_url"`
+	IsActive           bool           `bson:"is_active" json:"is_active"`
+	Role               string         `bson:"role" json:"role"` // "user", "client" or "admin"
+	HasSubscription    bool           `bson:"has_subscription" json:"has_subscription"`
+	SubscriptionExpiry time.Time      `bson:"subscription_expiry,omitempty" json:"subscription_expiry,omitempty"`
+	LastPaymentDate    time.Time      `bson:"last_payment_date,omitempty" json:"last_payment_date,omitempty"`
+	CreatedAt          time.Time      `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt          time.Time      `bson:"updated_at" json:"updated_at,omitempty"`
+
+	// Plan of the running subscription and its rank weight, copied on activation
+	// so listings can rank subscribers without joining subscriptions
+	SubscriptionPlanID *primitive.ObjectID `bson:"subscription_plan_id,omitempty" json:"subscription_plan_id,omitempty"`
+	SubscriptionRank   int                 `bson:"subscription_rank,omitempty" json:"-"`
+
+	// Paid "bump to top": listings put the profile first until boosted_until.
+	// boosted_at orders boosted profiles, the latest bump on top. A boost bought
+	// for a location applies to listings of that location or the places around
+	// it, held in boost_location_path (the union of both when one extends
+	// another); other boosts apply everywhere.
+	BoostedAt         *time.Time           `bson:"boosted_at,omitempty" json:"boosted_at,omitempty"`
+	BoostedUntil      *time.Time           `bson:"boosted_until,omitempty" json:"boosted_until,omitempty"`
+	BoostLocationPath []primitive.ObjectID `bson:"boost_location_path,omitempty" json:"-"`
+
+	// Telegram chat that notifications are sent to. It is linked by the user
+	// opening the bot with a one-time token, kept here as a SHA-256 hash until used.
+	TelegramChatID        string     `bson:"telegram_chat_id,omitempty" json:"telegram_chat_id,omitempty"`
+	TelegramLinkTokenHash string     `bson:"telegram_link_token_hash,omitempty" json:"-"`
+	TelegramLinkExpiresAt *time.Time `bson:"telegram_link_expires_at,omitempty" json:"-"`
+
+	// Cover photo chosen by the provider; image_url holds its card rendition once approved
+	PrimaryImageID *primitive.ObjectID `bson:"primary_image_id,omitempty" json:"primary_image_id,omitempty"`
+
+	// Watermark stamped on new photos; nil means the default (enabled, bottom right)
+	Watermark *WatermarkSettings `bson:"watermark,omitempty" json:"watermark,omitempty"`
+
+	// Set when an admin approves a selfie verification; shown as the verified badge
+	VerifiedAt *time.Time `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
+
+	// Age is computed from the date of birth, which can't change once an admin has
+	// checked it against an ID document. Profiles without a checked age stay hidden.
+	DateOfBirth   *time.Time `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+	AgeVerifiedAt *time.Time `bson:"age_verified_at,omitempty" json:"age_verified_at,omitempty"`
+
+	// Last time the user signed in or used an authenticated endpoint
+	LastActiveAt *time.Time `bson:"last_active_at,omitempty" json:"last_active_at,omitempty"`
+
+	// Place in the location taxonomy; location holds its label for display.
+	// location_path lists the county, town and area so listings can match any level.
+	LocationID   *primitive.ObjectID  `bson:"location_id,omitempty" json:"location_id,omitempty"`
+	LocationPath []primitive.ObjectID `bson:"location_path,omitempty" json:"-"`
+
+	// Services from before the catalogue that matched no catalogue entry
+	LegacyServices []string `bson:"legacy_services,omitempty" json:"legacy_services,omitempty"`
+
+	// Point used by the nearby search, already fuzzed when taken from a device
+	GeoLocation *GeoPoint `bson:"geo_location,omitempty" json:"-"`
+	GeoSource   string    `bson:"geo_source,omitempty" json:"geo_source,omitempty"`
 
 	// ADD THESE TWO LINES:
 	Advertised   bool      `bson:"advertised" json:"advertised"` // Has been posted to Telegram
@@ -48,6 +97,9 @@ type MinimalUserResponse struct {
 	Services        []string           `json:"services"`
 	Location        string             `json:"location"`         // Optional for homepage
 	HasSubscription bool               `json:"has_subscription"` // ADD THIS
+	Verified        bool               `json:"verified"`
+	Distance        string             `json:"distance,omitempty"`      // Only in nearby searches
+	FeaturedSlot    bool               `json:"featured_slot,omitempty"` // Placed in a featured slot of the page
 }
 
 // Subscription Model
@@ -81,4 +133,18 @@ type SubscriptionPlan struct {
 	Amount       float64            `bson:"amount" json:"amount"`               // 500, 1000, 3000
 	DurationDays int                `bson:"duration_days" json:"duration_days"` // 5, 14, 30
 	IsActive     bool               `bson:"is_active" json:"is_active"`
+
+	// Watermark placements subscribers may choose; without any only the default placement is used
+	WatermarkPositions []string `bson:"watermark_positions,omitempty" json:"watermark_positions,omitempty"`
+
+	// Listing placement: subscribers rank by weight, and plans with featured slots
+	// take that many of the featured positions on every listing page
+	RankWeight    int `bson:"rank_weight" json:"rank_weight"`
+	FeaturedSlots int `bson:"featured_slots,omitempty" json:"featured_slots,omitempty"`
+}
+
+// WatermarkSettings is a provider's choice of photo watermark
+type WatermarkSettings struct {
+	Enabled  bool   `bson:"enabled" json:"enabled"`
+	Position string `bson:"position" json:"position"`
 }
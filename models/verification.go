@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Verification is one attempt of a provider to prove the profile is genuine.
+// For a selfie the provider gets a code, photographs themselves holding it and
+// an admin compares the selfie with the profile photos. For an ID document the
+// admin checks the date of birth on the document against the one on the profile.
+type Verification struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID          primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Type            string             `bson:"type" json:"type"` // "selfie", "id_document"
+	Code            string             `bson:"code,omitempty" json:"code,omitempty"`
+	CodeExpiresAt   time.Time          `bson:"code_expires_at,omitempty" json:"code_expires_at,omitempty"`
+	DateOfBirth     *time.Time         `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"` // Claimed when an ID document was submitted
+	Status          string             `bson:"status" json:"status"`                                   // "awaiting_submission", "pending", "approved", "rejected"
+	FileKey         string             `bson:"file_key,omitempty" json:"-"`                            // Private bucket key of the submitted photo
+	RejectionReason string             `bson:"rejection_reason,omitempty" json:"rejection_reason,omitempty"`
+	ReviewedBy      string             `bson:"reviewed_by,omitempty" json:"-"`
+	ReviewedAt      *time.Time         `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	SubmittedAt     *time.Time         `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Verification types
+const (
+	VerificationTypeSelfie     = "selfie"
+	VerificationTypeIDDocument = "id_document"
+)
+
+// Verification statuses
+const (
+	VerificationAwaitingSubmission = "awaiting_submission"
+	VerificationPending            = "pending"
+	VerificationApproved           = "approved"
+	VerificationRejected           = "rejected"
+)
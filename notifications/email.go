@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"escort/models"
+)
+
+// emailNotifier sends plain text mail through the SMTP server in SMTP_HOST,
+// SMTP_PORT (587 by default), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+type emailNotifier struct{}
+
+func (emailNotifier) Ready(user models.User) error {
+	if os.Getenv("SMTP_HOST") == "" || os.Getenv("SMTP_FROM") == "" {
+		return errors.New("SMTP_HOST and SMTP_FROM are not set")
+	}
+	if user.Email == "" {
+		return errors.New("user has no email address")
+	}
+	return nil
+}
+
+func (emailNotifier) Notify(ctx context.Context, user models.User, msg Message) error {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	// Header values must stay on one line
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Title)
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		from, user.Email, subject, msg.Body)
+
+	// net/smtp has no context support; the send is bounded by the server's timeouts
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(host+":"+port, auth, from, []string{user.Email}, []byte(body))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
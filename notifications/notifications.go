@@ -0,0 +1,35 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"escort/database"
+	"escort/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification types
+const (
+	TypePhotoRejected        = "photo_rejected"
+	TypeVerificationApproved = "verification_approved"
+	TypeVerificationRejected = "verification_rejected"
+	TypeBoostActivated       = "boost_activated"
+	TypeSavedSearchMatches   = "saved_search_matches"
+)
+
+// Send stores a notification in the user's inbox
+func Send(ctx context.Context, userID primitive.ObjectID, notificationType, title, message string, data map[string]interface{}) error {
+	notification := models.Notification{
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+
+	_, err := database.NotificationCollection.InsertOne(ctx, notification)
+	return err
+}
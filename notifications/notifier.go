@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"escort/models"
+)
+
+// Delivery channels
+const (
+	ChannelInApp    = "in_app"
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+)
+
+// Message is a notification independent of the channel that delivers it
+type Message struct {
+	Type  string
+	Title string
+	Body  string
+	Data  map[string]interface{}
+}
+
+// Notifier delivers messages over one channel
+type Notifier interface {
+	// Ready reports why user can't be reached over the channel, nil if they can
+	Ready(user models.User) error
+	Notify(ctx context.Context, user models.User, msg Message) error
+}
+
+var (
+	notifiersMu sync.RWMutex
+	notifiers   = map[string]Notifier{
+		ChannelInApp:    inAppNotifier{},
+		ChannelEmail:    emailNotifier{},
+		ChannelTelegram: telegramNotifier{},
+	}
+)
+
+// Register adds or replaces the notifier of a channel
+func Register(channel string, notifier Notifier) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	notifiers[channel] = notifier
+}
+
+// Lookup returns the notifier of a channel
+func Lookup(channel string) (Notifier, bool) {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	notifier, ok := notifiers[channel]
+	return notifier, ok
+}
+
+// Deliver sends msg to user over channel. Messages that can't go out there land
+// in the in-app inbox instead, so nothing is lost to a misconfigured channel.
+func Deliver(ctx context.Context, channel string, user models.User, msg Message) error {
+	notifier, ok := Lookup(channel)
+	if !ok {
+		fmt.Printf("⚠️ Unknown notification channel %q, using in-app\n", channel)
+		return inAppNotifier{}.Notify(ctx, user, msg)
+	}
+
+	err := notifier.Ready(user)
+	if err == nil {
+		err = notifier.Notify(ctx, user, msg)
+	}
+	if err != nil && channel != ChannelInApp {
+		fmt.Printf("⚠️ Could not notify %s over %s, using in-app: %v\n", user.ID.Hex(), channel, err)
+		return inAppNotifier{}.Notify(ctx, user, msg)
+	}
+	return err
+}
+
+// inAppNotifier stores messages in the user's inbox
+type inAppNotifier struct{}
+
+func (inAppNotifier) Ready(models.User) error { return nil }
+
+func (inAppNotifier) Notify(ctx context.Context, user models.User, msg Message) error {
+	return Send(ctx, user.ID, msg.Type, msg.Title, msg.Body, msg.Data)
+}
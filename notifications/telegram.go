@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"escort/models"
+)
+
+// telegramNotifier sends messages from the bot in TELEGRAM_BOT_TOKEN to the
+// user's telegram_chat_id
+type telegramNotifier struct{}
+
+func (telegramNotifier) Ready(user models.User) error {
+	if os.Getenv("TELEGRAM_BOT_TOKEN") == "" {
+		return errors.New("TELEGRAM_BOT_TOKEN is not set")
+	}
+	if user.TelegramChatID == "" {
+		return errors.New("user has no Telegram chat")
+	}
+	return nil
+}
+
+func (telegramNotifier) Notify(ctx context.Context, user models.User, msg Message) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  user.TelegramChatID,
+		"text":                     msg.Title + "\n\n" + msg.Body,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", os.Getenv("TELEGRAM_BOT_TOKEN"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram: %s", result.Description)
+	}
+	return nil
+}
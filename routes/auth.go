@@ -1,17 +1,11 @@
 package routes
 
 import (
-	"bytes"
 	"context"
 	"escort/controllers"
 	"escort/database"
 	"escort/middleware"
 	"escort/models"
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -29,9 +23,19 @@ func AuthRoutes(route *gin.Engine) {
 	// GET /search?q=nairobi
 	route.GET("/search", controllers.SearchUsers)
 
+	// GET /search/nearby?lat=-1.26&lng=36.80&radius=5
+	route.GET("/search/nearby", controllers.SearchNearby)
+
 	// GET /location/nairobi
 	route.GET("location/:location", controllers.GetUsersByLocation)
 
+	// Location taxonomy: GET /locations?type=county, GET /locations/nairobi
+	route.GET("/locations", controllers.GetLocations)
+	route.GET("/locations/:slug", controllers.GetLocation)
+
+	// Services catalogue: GET /services
+	route.GET("/services", controllers.GetServiceCatalogue)
+
 	//Get Subscription Plans
 	route.GET("/subscription/plans", controllers.GetSubscriptionPlans)
 
@@ -54,20 +58,48 @@ func AuthRoutes(route *gin.Engine) {
 			return
 		}
 
-		// Find user with detailed information
-		var user bson.M
+		// Find user with detailed information; only profiles listings may show
+		var raw bson.Raw
 		err = database.UserCollection.FindOne(context.Background(),
-			bson.M{"_id": userObjID}).Decode(&user)
+			controllers.PublicProfileFilter(userObjID, time.Now())).Decode(&raw)
 
 		if err != nil {
 			c.JSON(404, gin.H{"error": "User not found"})
 			return
 		}
 
+		var user bson.M
+		var photos struct {
+			Images []models.ProfileImage `bson:"images"`
+		}
+		if err := bson.Unmarshal(raw, &user); err != nil {
+			c.JSON(500, gin.H{"error": "Failed to read user"})
+			return
+		}
+		bson.Unmarshal(raw, &photos)
+
+		// The age is kept current from the date of birth, which itself stays private
+		if dob, ok := user["date_of_birth"].(primitive.DateTime); ok {
+			user["age"] = models.AgeOn(dob.Time(), time.Now())
+		}
+
 		// Remove sensitive information
 		delete(user, "password")
 		delete(user, "email")
 		delete(user, "role")
+		delete(user, "date_of_birth")
+		delete(user, "geo_location")
+		delete(user, "geo_source")
+		delete(user, "telegram_chat_id")
+		delete(user, "telegram_link_token_hash")
+		delete(user, "telegram_link_expires_at")
+		delete(user, "boost_location_path")
+
+		// Only approved photos are public
+		user["images"] = models.PublicImages(photos.Images)
+
+		// Verification badge
+		user["verified"] = user["verified_at"] != nil
 
 		// Format response
 		c.JSON(200, gin.H{
@@ -81,6 +113,7 @@ func AuthRoutes(route *gin.Engine) {
 	{
 		auth.POST("/login", controllers.LoginUser)
 		auth.POST("/register", controllers.RegisterUser)
+		auth.POST("/register/client", controllers.RegisterClient)
 
 		auth.GET("/debug/subscriptions", func(c *gin.Context) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -109,8 +142,12 @@ func AuthRoutes(route *gin.Engine) {
 			})
 		})
 
+		// Signed-in accounts of any role
+		account := auth.Group("").Use(middleware.RequireAuth())
+
 		// ====== SUBSCRIPTION ROUTES ======
-		protected := auth.Group("").Use(middleware.RequireAuth())
+		// Provider accounts
+		protected := auth.Group("").Use(middleware.RequireAuth(), middleware.RequireRole("user"))
 		// 1. subscribe - Initiate MPESA payment
 		protected.POST("/subscribe", controllers.Subscribe)
 
@@ -120,8 +157,23 @@ func AuthRoutes(route *gin.Engine) {
 		//3. Check user's current subscription status
 		protected.GET("/subscription/status", controllers.GetUserSubscriptionStatus)
 
+		// "Bump to top" boosts, paid through the same MPESA flow
+		protected.GET("/boost/options", controllers.GetBoostOptions)
+		protected.POST("/boost", controllers.PurchaseBoost)
+		protected.GET("/boosts", controllers.GetMyBoosts)
+
+		// Client accounts: saved searches notify them of new matching profiles
+		client := auth.Group("/client").Use(middleware.RequireAuth(), middleware.RequireRole("client"))
+		client.POST("/telegram", controllers.StartTelegramLink)
+		client.DELETE("/telegram", controllers.UnlinkTelegram)
+		client.GET("/saved-searches", controllers.GetSavedSearches)
+		client.POST("/saved-searches", controllers.CreateSavedSearch)
+		client.PUT("/saved-searches/:id", controllers.UpdateSavedSearch)
+		client.DELETE("/saved-searches/:id", controllers.DeleteSavedSearch)
+		client.GET("/saved-searches/:id/results", controllers.GetSavedSearchResults)
+
 		// Add to routes/auth.go
-		protected.GET("/me", func(c *gin.Context) {
+		account.GET("/me", func(c *gin.Context) {
 			userID, exists := c.Get("userID")
 			if !exists {
 				c.JSON(401, gin.H{"error": "Not authenticated"})
@@ -157,10 +209,11 @@ func AuthRoutes(route *gin.Engine) {
 				FirstName         string   `json:"first_name"`
 				LastName          string   `json:"last_name"`
 				PhoneNo           string   `json:"phone_no"`
-				Location          string   `json:"location"`
+				LocationID        string   `json:"location_id"` // From GET /locations
+				Location          string   `json:"location"`    // Name, slug or alias when no location_id is sent
 				Gender            string   `json:"gender"`
 				SexualOrientation string   `json:"sexual_orientation"`
-				Age               int      `json:"age"`
+				DateOfBirth       string   `json:"date_of_birth"` // YYYY-MM-DD
 				Nationality       string   `json:"nationality"`
 				Services          []string `json:"services"`
 			}
@@ -188,12 +241,9 @@ func AuthRoutes(route *gin.Engine) {
 				"first_name":         updateData.FirstName,
 				"last_name":          updateData.LastName,
 				"phone_no":           updateData.PhoneNo,
-				"location":           updateData.Location,
 				"gender":             updateData.Gender,
 				"sexual_orientation": updateData.SexualOrientation,
-				"age":                updateData.Age,
 				"nationality":        updateData.Nationality,
-				"services":           updateData.Services,
 				"updated_at":         time.Now(),
 			}
 
@@ -205,12 +255,79 @@ func AuthRoutes(route *gin.Engine) {
 				}
 			}
 
+			// Services come from the catalogue; an empty list clears them
+			if updateData.Services != nil {
+				services, err := controllers.NormalizeServices(context.Background(), updateData.Services)
+				if err != nil {
+					c.JSON(400, gin.H{
+						"success": false,
+						"error":   err.Error(),
+					})
+					return
+				}
+				cleanUpdateDoc["services"] = services
+			}
+
+			// Locations must come from the taxonomy
+			var location *models.Location
+			if locationValue := updateData.LocationID; locationValue != "" || updateData.Location != "" {
+				if locationValue == "" {
+					locationValue = updateData.Location
+				}
+				var err error
+				if location, err = controllers.ResolveLocation(context.Background(), locationValue); err != nil {
+					c.JSON(400, gin.H{
+						"success": false,
+						"error":   "Unknown location. Choose one from /locations",
+					})
+					return
+				}
+			}
+
+			// The age follows the date of birth, which is locked once an admin has verified it
+			if updateData.DateOfBirth != "" {
+				now := time.Now()
+				dateOfBirth, err := models.ParseDateOfBirth(updateData.DateOfBirth, now)
+				if err != nil {
+					c.JSON(400, gin.H{
+						"success": false,
+						"error":   err.Error(),
+					})
+					return
+				}
+
+				var current models.User
+				if err := database.UserCollection.FindOne(context.Background(), bson.M{"_id": userObjID}).Decode(&current); err != nil {
+					c.JSON(404, gin.H{
+						"success": false,
+						"error":   "User not found",
+					})
+					return
+				}
+				if current.AgeVerifiedAt != nil {
+					if current.DateOfBirth == nil || !current.DateOfBirth.Equal(dateOfBirth) {
+						c.JSON(403, gin.H{
+							"success": false,
+							"error":   "Your date of birth has been verified and can no longer be changed",
+						})
+						return
+					}
+				} else {
+					cleanUpdateDoc["date_of_birth"] = dateOfBirth
+					cleanUpdateDoc["age"] = models.AgeOn(dateOfBirth, now)
+				}
+			}
+
 			_, err := database.UserCollection.UpdateOne(
 				context.Background(),
 				bson.M{"_id": userObjID},
 				bson.M{"$set": cleanUpdateDoc},
 			)
 
+			if err == nil && location != nil {
+				err = controllers.PlaceUserAt(context.Background(), userObjID, location)
+			}
+
 			if err != nil {
 				c.JSON(500, gin.H{
 					"success": false,
@@ -242,270 +359,35 @@ func AuthRoutes(route *gin.Engine) {
 			})
 		})
 
-		// ==================== FIXED UPLOAD-IMAGES ROUTE (AWS SDK V2) ====================
-		protected.POST("/upload-images", func(c *gin.Context) {
-			userID, exists := c.Get("userID")
-			if !exists {
-				c.JSON(401, gin.H{"error": "Not authenticated"})
-				return
-			}
-
-			// Parse multipart form
-			err := c.Request.ParseMultipartForm(50 << 20) // 50MB max
-			if err != nil {
-				c.JSON(400, gin.H{"error": "Failed to parse form: " + err.Error()})
-				return
-			}
-
-			// Get files from form
-			form, err := c.MultipartForm()
-			if err != nil {
-				c.JSON(400, gin.H{"error": "Failed to get multipart form: " + err.Error()})
-				return
-			}
-
-			files := form.File["images"]
-			if len(files) == 0 {
-				c.JSON(400, gin.H{"error": "No images provided"})
-				return
-			}
-
-			// Limit to 5 files per upload
-			if len(files) > 5 {
-				files = files[:5]
-			}
-
-			userObjID, _ := primitive.ObjectIDFromHex(userID.(string))
-
-			// Get current user to check existing images
-			var existingUser map[string]interface{}
-			err = database.UserCollection.FindOne(context.Background(), bson.M{"_id": userObjID}).Decode(&existingUser)
-			if err != nil {
-				c.JSON(500, gin.H{"error": "Failed to fetch user: " + err.Error()})
-				return
-			}
-
-			// Get existing images
-			var existingImages []string
-			if imagesInterface, ok := existingUser["images"]; ok && imagesInterface != nil {
-				if imagesArray, ok := imagesInterface.(primitive.A); ok {
-					for _, img := range imagesArray {
-						if str, ok := img.(string); ok && str != "" {
-							existingImages = append(existingImages, str)
-						}
-					}
-				}
-			}
-
-			// Check if adding new files would exceed 5 total
-			if len(existingImages)+len(files) > 5 {
-				c.JSON(400, gin.H{
-					"error": fmt.Sprintf("Maximum 5 photos allowed. You have %d photos, trying to add %d more",
-						len(existingImages), len(files)),
-				})
-				return
-			}
-
-			// Initialize Supabase Storage
-			supabaseURL := os.Getenv("SUPABASE_URL")
-			supabaseKey := os.Getenv("SUPABASE_SERVICE_KEY")
-			supabaseBucket := "profiles"
-
-			fmt.Printf("☁️ Supabase Config - URL: %s\n", supabaseURL)
-
-			if supabaseURL == "" || supabaseKey == "" {
-				c.JSON(500, gin.H{"error": "Supabase configuration missing"})
-				return
-			}
-
-			var uploadedImageUrls []string
-
-			for _, file := range files {
-				src, err := file.Open()
-				if err != nil {
-					c.JSON(500, gin.H{"error": "Failed to open file: " + err.Error()})
-					return
-				}
-				defer src.Close()
-
-				// Read file content
-				fileBytes, err := io.ReadAll(src)
-				if err != nil {
-					c.JSON(500, gin.H{"error": "Failed to read file: " + err.Error()})
-					return
-				}
-
-				// Create unique filename
-				timestamp := time.Now().UnixNano()
-				safeName := strings.ReplaceAll(file.Filename, " ", "_")
-				fileName := fmt.Sprintf("%s/%d_%s", userID.(string), timestamp, safeName)
-
-				// Get content type
-				contentType := file.Header.Get("Content-Type")
-				if contentType == "" {
-					contentType = "image/jpeg"
-				}
-
-				// Upload to Supabase Storage
-				uploadURL := fmt.Sprintf("%s/storage/v1/object/%s/%s", supabaseURL, supabaseBucket, fileName)
-
-				req, err := http.NewRequest("POST", uploadURL, bytes.NewReader(fileBytes))
-				if err != nil {
-					c.JSON(500, gin.H{"error": "Failed to create upload request: " + err.Error()})
-					return
-				}
-
-				req.Header.Set("Authorization", "Bearer "+supabaseKey)
-				req.Header.Set("Content-Type", contentType)
-				req.Header.Set("x-upsert", "true")
-
-				client := &http.Client{}
-				resp, err := client.Do(req)
-				if err != nil {
-					c.JSON(500, gin.H{"error": "Failed to upload to Supabase: " + err.Error()})
-					return
-				}
-				defer resp.Body.Close()
-
-				respBody, _ := io.ReadAll(resp.Body)
-				fmt.Printf("☁️ Supabase response status: %d, body: %s\n", resp.StatusCode, string(respBody))
-
-				if resp.StatusCode != 200 && resp.StatusCode != 201 {
-					c.JSON(500, gin.H{"error": fmt.Sprintf("Supabase upload failed: %s", string(respBody))})
-					return
-				}
-
-				// Build public URL
-				publicURL := fmt.Sprintf("%s/storage/v1/object/public/%s/%s", supabaseURL, supabaseBucket, fileName)
-				fmt.Printf("✅ Supabase upload success! URL: %s\n", publicURL)
-				uploadedImageUrls = append(uploadedImageUrls, publicURL)
-			}
-
-			// Combine existing images with new ones
-			allImages := append(existingImages, uploadedImageUrls...)
-
-			// Update user with all images
-			updateData := bson.M{
-				"images":     allImages,
-				"image_url":  allImages[0],
-				"updated_at": time.Now(),
-			}
-
-			_, err = database.UserCollection.UpdateOne(
-				context.Background(),
-				bson.M{"_id": userObjID},
-				bson.M{"$set": updateData},
-			)
-
-			if err != nil {
-				c.JSON(500, gin.H{"error": "Failed to update user: " + err.Error()})
-				return
-			}
-
-			c.JSON(200, gin.H{
-				"success":     true,
-				"message":     "Images uploaded successfully",
-				"imageUrls":   uploadedImageUrls,
-				"totalImages": len(allImages),
-				"maxAllowed":  5,
-			})
-		})
-
-		// Add this DELETE endpoint to your protected routes
-		protected.DELETE("/delete-image", func(c *gin.Context) {
-			fmt.Printf("DELETE /auth/delete-image route hit!\n")
-			userID, exists := c.Get("userID")
-			if !exists {
-				c.JSON(401, gin.H{"error": "Not authenticated"})
-				return
-			}
-
-			var request struct {
-				ImageURL string `json:"imageUrl"`
-			}
-
-			if err := c.ShouldBindJSON(&request); err != nil {
-				c.JSON(400, gin.H{"error": "Invalid request: " + err.Error()})
-				return
-			}
-
-			if request.ImageURL == "" {
-				c.JSON(400, gin.H{"error": "Image URL is required"})
-				return
-			}
-
-			userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-			if err != nil {
-				c.JSON(400, gin.H{"error": "Invalid user ID"})
-				return
-			}
-
-			// First, get current user to check images
-			var existingUser map[string]interface{}
-			err = database.UserCollection.FindOne(context.Background(), bson.M{"_id": userObjID}).Decode(&existingUser)
-			if err != nil {
-				c.JSON(500, gin.H{"error": "Failed to fetch user: " + err.Error()})
-				return
-			}
-
-			// Get current images array
-			var currentImages []string
-			if imagesInterface, ok := existingUser["images"]; ok && imagesInterface != nil {
-				if imagesArray, ok := imagesInterface.(primitive.A); ok {
-					for _, img := range imagesArray {
-						if str, ok := img.(string); ok && str != "" {
-							currentImages = append(currentImages, str)
-						}
-					}
-				}
-			}
-
-			// Check if image exists in user's images
-			imageIndex := -1
-			for i, img := range currentImages {
-				if img == request.ImageURL {
-					imageIndex = i
-					break
-				}
-			}
-
-			if imageIndex == -1 {
-				c.JSON(404, gin.H{"error": "Image not found in user's photos"})
-				return
-			}
-
-			// Remove image from array
-			updatedImages := append(currentImages[:imageIndex], currentImages[imageIndex+1:]...)
-
-			// Update user with new images array
-			updateData := bson.M{
-				"images":     updatedImages,
-				"updated_at": time.Now(),
-			}
-
-			// Set image_url to first image if available
-			if len(updatedImages) > 0 {
-				updateData["image_url"] = updatedImages[0]
-			} else {
-				updateData["image_url"] = ""
-			}
-
-			_, err = database.UserCollection.UpdateOne(
-				context.Background(),
-				bson.M{"_id": userObjID},
-				bson.M{"$set": updateData},
-			)
-
-			if err != nil {
-				c.JSON(500, gin.H{"error": "Failed to update user: " + err.Error()})
-				return
-			}
-
-			c.JSON(200, gin.H{
-				"success":         true,
-				"message":         "Image deleted successfully",
-				"remainingImages": len(updatedImages),
-			})
-		})
+		// Profile photos (stored through the configured object store)
+		protected.POST("/upload-images", controllers.UploadProfileImages)
+		protected.DELETE("/delete-image", controllers.DeleteProfileImage)
+		protected.PUT("/images/order", controllers.ReorderProfileImages)
+		protected.PUT("/images/:id/primary", controllers.SetPrimaryProfileImage)
+		protected.PUT("/images/:id/caption", controllers.UpdateProfileImageCaption)
+
+		// Resumable (tus) photo uploads
+		protected.POST("/uploads", controllers.CreateUpload)
+		protected.HEAD("/uploads/:id", controllers.GetUploadOffset)
+		protected.GET("/uploads/:id", controllers.GetUpload)
+		protected.PATCH("/uploads/:id", controllers.PatchUpload)
+		protected.DELETE("/uploads/:id", controllers.DeleteUpload)
+
+		// Position used by the nearby search
+		protected.PUT("/geo-location", controllers.SetGeoLocation)
+		protected.DELETE("/geo-location", controllers.ClearGeoLocation)
+
+		// Profile verification
+		protected.GET("/verification", controllers.GetVerificationStatus)
+		protected.POST("/verification/start", controllers.StartVerification)
+		protected.POST("/verification/selfie", controllers.SubmitVerificationSelfie)
+		protected.POST("/verification/id-document", controllers.SubmitIDDocument)
+
+		protected.GET("/watermark", controllers.GetWatermarkSettings)
+		protected.PUT("/watermark", controllers.UpdateWatermarkSettings)
+
+		// In-app notifications
+		account.GET("/notifications", controllers.GetNotifications)
+		account.PUT("/notifications/read", controllers.MarkNotificationsRead)
 	}
 }
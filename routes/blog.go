@@ -11,7 +11,11 @@ func BlogRoutes(router *gin.Engine) {
 	{
 		blogRoutes.POST("", controllers.CreateBlog)
 		blogRoutes.GET("", controllers.GetAllBlogs)
+		blogRoutes.GET("/categories", controllers.GetBlogCategories)
+		blogRoutes.GET("/tags", controllers.GetBlogTags)
+		blogRoutes.GET("/search", controllers.SearchBlogs)
 		blogRoutes.GET("/:slug", controllers.GetBlogBySlug)
+		blogRoutes.GET("/:slug/related", controllers.GetRelatedBlogs)
 		blogRoutes.PUT("/:id", controllers.UpdateBlog)
 		blogRoutes.DELETE("/:id", controllers.DeleteBlog)
 	}
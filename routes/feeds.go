@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"escort/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func FeedRoutes(router *gin.Engine) {
+	// Machine-readable feeds for SEO
+	router.GET("/feeds/blog.rss", controllers.GetBlogRSS)
+	router.GET("/feeds/blog.atom", controllers.GetBlogAtom)
+
+	router.GET("/sitemap.xml", controllers.GetSitemap)
+	router.GET("/sitemaps/:file", controllers.GetSitemapFile)
+}
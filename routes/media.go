@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"escort/controllers"
+	"escort/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MediaRoutes(router *gin.Engine) {
+	// Blog media library, editors only
+	mediaRoutes := router.Group("/api/media").Use(middleware.RequireAdmin())
+	{
+		mediaRoutes.POST("", controllers.UploadMedia)
+		mediaRoutes.GET("", controllers.ListMedia)
+		mediaRoutes.GET("/:id", controllers.GetMedia)
+		mediaRoutes.PUT("/:id", controllers.UpdateMedia)
+		mediaRoutes.DELETE("/:id", controllers.DeleteMedia)
+	}
+}
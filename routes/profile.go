@@ -28,5 +28,30 @@ func AdminRoutes(route *gin.Engine) {
 		// Reports
 		adminGroup.GET("/reports/users", admin.GetUserReport)
 		adminGroup.GET("/reports/subscriptions", admin.GetSubscriptionReport)
+
+		// Photo moderation
+		adminGroup.GET("/photos", admin.GetPhotoQueue)
+		adminGroup.POST("/photos/approve", admin.ApprovePhotos)
+		adminGroup.POST("/photos/reject", admin.RejectPhotos)
+
+		// Services catalogue
+		adminGroup.GET("/services", admin.GetServices)
+		adminGroup.POST("/services", admin.CreateService)
+		adminGroup.PUT("/services/:id", admin.UpdateService)
+
+		// Profile verification review
+		adminGroup.GET("/verifications", admin.GetVerificationQueue)
+		adminGroup.POST("/verifications/:id/approve", admin.ApproveVerification)
+		adminGroup.POST("/verifications/:id/reject", admin.RejectVerification)
+		adminGroup.DELETE("/users/:id/verification", admin.RevokeVerification)
+
+		// "Bump to top" boost prices per location
+		adminGroup.GET("/boost-options", admin.GetBoostOptions)
+		adminGroup.POST("/boost-options", admin.CreateBoostOption)
+		adminGroup.PUT("/boost-options/:id", admin.UpdateBoostOption)
+		adminGroup.DELETE("/boost-options/:id", admin.DeleteBoostOption)
+
+		// Storage maintenance
+		adminGroup.POST("/storage/gc", admin.CollectOrphanedImages)
 	}
 }
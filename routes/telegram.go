@@ -15,5 +15,8 @@ func TelegramRoutes(route *gin.Engine) {
 
 		// Mark creator as advertised
 		telegramGroup.POST("/creators/:id/mark-advertised", controllers.MarkCreatorAdvertised)
+
+		// Bot updates; /start links a client's chat for notifications
+		telegramGroup.POST("/webhook", controllers.TelegramWebhook)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps objects on the local filesystem under LocalStorageDir()/<bucket>
+type LocalStore struct {
+	bucket  string
+	root    string
+	baseURL string
+}
+
+func NewLocalStore(bucket string) *LocalStore {
+	return &LocalStore{
+		bucket:  bucket,
+		root:    filepath.Join(LocalStorageDir(), bucket),
+		baseURL: localStorageURL(),
+	}
+}
+
+// LocalStorageDir holds one directory per bucket; those of PublicBuckets are served at /uploads
+func LocalStorageDir() string {
+	return getEnv("LOCAL_STORAGE_DIR", "./uploads")
+}
+
+// localStorageURL is the public base URL of this API, used to build object URLs
+func localStorageURL() string {
+	if url := os.Getenv("LOCAL_STORAGE_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return "http://localhost:" + getEnv("PORT", "8080")
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temp file first so readers never see a half-written object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) PublicURL(key string) string {
+	return fmt.Sprintf("%s/uploads/%s/%s", s.baseURL, s.bucket, strings.TrimLeft(key, "/"))
+}
+
+func (s *LocalStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(expiry).Unix()
+	signature, err := signLocalObject(s.bucket, key, expires)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/storage/signed/%s/%s?expires=%d&signature=%s", s.baseURL, s.bucket, key, expires, signature), nil
+}
+
+func (s *LocalStore) KeyFromURL(url string) (string, bool) {
+	prefix := fmt.Sprintf("%s/uploads/%s/", s.baseURL, s.bucket)
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key, err := cleanObjectKey(strings.TrimPrefix(url, prefix))
+	return key, err == nil
+}
+
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		// Skip directories and in-progress writes
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), UpdatedAt: info.ModTime()})
+		return nil
+	})
+
+	return objects, err
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// ErrNoSigningKey is returned when signed URLs are needed but no key is configured
+var ErrNoSigningKey = errors.New("STORAGE_SIGNING_KEY (or JWT_SECRET) must be set to sign storage URLs")
+
+// localSigningKey is STORAGE_SIGNING_KEY, or JWT_SECRET without it. There is no
+// default: with an empty key anyone could forge URLs to private objects.
+func localSigningKey() ([]byte, error) {
+	secret := os.Getenv("STORAGE_SIGNING_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		return nil, ErrNoSigningKey
+	}
+	return []byte(secret), nil
+}
+
+// signLocalObject signs bucket/key/expiry with the local signing key
+func signLocalObject(bucket, key string, expires int64) (string, error) {
+	secret, err := localSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(bucket + "/" + key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// LocalSignedObjectPath validates a signed URL produced by LocalStore.SignedURL and
+// returns the file it grants access to
+func LocalSignedObjectPath(bucket, key, expires, signature string) (string, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", fmt.Errorf("signed URL expired")
+	}
+
+	key, err = cleanObjectKey(key)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(bucket, "/") || strings.Contains(bucket, "..") {
+		return "", fmt.Errorf("invalid bucket")
+	}
+
+	expected, err := signLocalObject(bucket, key, expiresAt)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", fmt.Errorf("invalid signature")
+	}
+
+	return NewLocalStore(bucket).path(key)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	phashSampleSize = 32 // the image is reduced to 32x32 grey pixels
+	phashBlockSize  = 8  // of which the lowest 8x8 frequencies make up the hash
+)
+
+// phashCosines[u][x] caches cos((2x+1)uπ/2N) for the DCT
+var phashCosines = func() [phashBlockSize + 1][phashSampleSize]float64 {
+	var table [phashBlockSize + 1][phashSampleSize]float64
+	for u := range table {
+		for x := 0; x < phashSampleSize; x++ {
+			table[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * phashSampleSize))
+		}
+	}
+	return table
+}()
+
+// PerceptualHash returns a 64 bit DCT hash of img. Resized, recompressed or
+// lightly edited copies of a photo hash to values a few bits apart.
+func PerceptualHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, phashSampleSize, phashSampleSize))
+	draw.CatmullRom.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	// Low frequency DCT coefficients, skipping the first row and column which
+	// only carry overall brightness
+	coefficients := make([]float64, 0, phashBlockSize*phashBlockSize)
+	for u := 1; u <= phashBlockSize; u++ {
+		for v := 1; v <= phashBlockSize; v++ {
+			sum := 0.0
+			for y := 0; y < phashSampleSize; y++ {
+				row := 0.0
+				for x := 0; x < phashSampleSize; x++ {
+					row += float64(gray.GrayAt(x, y).Y) * phashCosines[u][x]
+				}
+				sum += row * phashCosines[v][y]
+			}
+			coefficients = append(coefficients, sum)
+		}
+	}
+
+	sorted := append([]float64(nil), coefficients...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, coefficient := range coefficients {
+		if coefficient > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// FormatPerceptualHash encodes a hash as the 16 character hex string stored in the database
+func FormatPerceptualHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParsePerceptualHash decodes a hash stored by FormatPerceptualHash
+func ParsePerceptualHash(value string) (uint64, error) {
+	return strconv.ParseUint(value, 16, 64)
+}
+
+// HashDistance is the number of differing bits between two hashes
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// PerceptualHashBands splits hash into count runs of neighbouring bits and keys
+// each one by its position and value ("3:1f"). Hashes that differ in fewer than
+// count bits leave at least one run untouched, so they always share a band.
+func PerceptualHashBands(hash uint64, count int) []string {
+	count = max(1, min(count, 64))
+	bands := make([]string, 0, count)
+	start := 0
+	for i := 0; i < count; i++ {
+		// The first 64%count bands get one bit more
+		width := 64 / count
+		if i < 64%count {
+			width++
+		}
+		value := (hash >> uint(start)) & (1<<uint(width) - 1)
+		bands = append(bands, fmt.Sprintf("%d:%x", i, value))
+		start += width
+	}
+	return bands
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+func TestHashDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0xffffffffffffffff, 0xffffffffffffffff, 0},
+		{0, 1, 1},
+		{0, 0x8000000000000000, 1},
+		{0xf0, 0x0f, 8},
+		{0x00ff00ff00ff00ff, 0xff00ff00ff00ff00, 64},
+		{0, 0xffffffffffffffff, 64},
+		{0x1234, 0x1235, 1},
+	}
+	for _, tt := range tests {
+		if got := HashDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HashDistance(%#x, %#x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := HashDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("HashDistance(%#x, %#x) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestPerceptualHashFormat(t *testing.T) {
+	tests := []struct {
+		hash uint64
+		want string
+	}{
+		{0, "0000000000000000"},
+		{1, "0000000000000001"},
+		{0xdeadbeef, "00000000deadbeef"},
+		{0xffffffffffffffff, "ffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		formatted := FormatPerceptualHash(tt.hash)
+		if formatted != tt.want {
+			t.Errorf("FormatPerceptualHash(%#x) = %q, want %q", tt.hash, formatted, tt.want)
+		}
+		parsed, err := ParsePerceptualHash(formatted)
+		if err != nil || parsed != tt.hash {
+			t.Errorf("ParsePerceptualHash(%q) = %#x, %v; want %#x", formatted, parsed, err, tt.hash)
+		}
+	}
+
+	for _, value := range []string{"", "xyz", "10000000000000000"} {
+		if _, err := ParsePerceptualHash(value); err == nil {
+			t.Errorf("ParsePerceptualHash(%q) succeeded", value)
+		}
+	}
+}
+
+// testPhoto draws a photo-like image of shapes over a gradient; seed varies the layout
+func testPhoto(width, height, seed int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			shade := uint8(255 * (x + y) / (width + height))
+			img.Set(x, y, color.RGBA{shade, shade / 2, 255 - shade, 255})
+		}
+	}
+	for i := 0; i < 4; i++ {
+		x0 := (seed*37 + i*53) % (width / 2)
+		y0 := (seed*61 + i*29) % (height / 2)
+		fill := color.RGBA{uint8(seed * 90), uint8(i * 60), uint8(255 - seed*40), 255}
+		draw.Draw(img, image.Rect(x0, y0, x0+width/4, y0+height/3), &image.Uniform{fill}, image.Point{}, draw.Src)
+	}
+	return img
+}
+
+func TestPerceptualHashDistance(t *testing.T) {
+	original := testPhoto(400, 300, 1)
+	hash := PerceptualHash(original)
+
+	resized := image.NewRGBA(image.Rect(0, 0, 160, 120))
+	draw.ApproxBiLinear.Scale(resized, resized.Bounds(), original, original.Bounds(), draw.Src, nil)
+
+	tests := []struct {
+		name    string
+		img     image.Image
+		maxDist int
+		minDist int
+	}{
+		{"same photo", original, 0, 0},
+		{"resized copy", resized, 6, 0},
+		{"different photo", testPhoto(400, 300, 3), 64, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance := HashDistance(hash, PerceptualHash(tt.img))
+			if distance > tt.maxDist || distance < tt.minDist {
+				t.Errorf("distance %d, want %d to %d", distance, tt.minDist, tt.maxDist)
+			}
+		})
+	}
+}
+
+func TestPerceptualHashBands(t *testing.T) {
+	tests := []struct {
+		hash  uint64
+		count int
+		want  []string
+	}{
+		{0, 4, []string{"0:0", "1:0", "2:0", "3:0"}},
+		{0x0123456789abcdef, 4, []string{"0:cdef", "1:89ab", "2:4567", "3:123"}},
+		{0xffffffffffffffff, 1, []string{"0:ffffffffffffffff"}},
+		{0xffffffffffffffff, 3, []string{"0:3fffff", "1:1fffff", "2:1fffff"}},
+		{0xdeadbeef, 0, []string{"0:deadbeef"}},
+	}
+	for _, tt := range tests {
+		if got := PerceptualHashBands(tt.hash, tt.count); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PerceptualHashBands(%#x, %d) = %q, want %q", tt.hash, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestPerceptualHashBandsShareOneWithinDistance(t *testing.T) {
+	const count = 11
+	hash := uint64(0x9e3779b97f4a7c15)
+
+	// Flip count-1 bits spread over the hash, one in each of the first bands
+	flipped := hash
+	for i := 0; i < count-1; i++ {
+		flipped ^= 1 << uint(i*6)
+	}
+	if HashDistance(hash, flipped) != count-1 {
+		t.Fatalf("test hashes are %d bits apart", HashDistance(hash, flipped))
+	}
+
+	shared := 0
+	bands := PerceptualHashBands(flipped, count)
+	for i, band := range PerceptualHashBands(hash, count) {
+		if bands[i] == band {
+			shared++
+		}
+	}
+	if shared == 0 {
+		t.Error("hashes within the distance share no band")
+	}
+}
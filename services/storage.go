@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ObjectStore stores files (photos, media) in a single bucket of some storage backend
+type ObjectStore interface {
+	// Put writes body to key, replacing any existing object
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens the object at key for reading; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PublicURL is the permanent URL of key in a public bucket
+	PublicURL(key string) string
+	// SignedURL is a URL granting temporary read access to key in a private bucket
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// KeyFromURL maps a URL returned by PublicURL back to its key
+	KeyFromURL(url string) (string, bool)
+	// List returns every object whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// ErrObjectNotFound is returned by Get for missing objects
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key       string
+	Size      int64
+	UpdatedAt time.Time
+}
+
+// NewObjectStore returns the store for bucket using the backend chosen by STORAGE_BACKEND
+// ("local" or "supabase"). Without it, Supabase is used when configured and the local
+// filesystem otherwise, so development runs fully offline.
+func NewObjectStore(bucket string) (ObjectStore, error) {
+	backend := strings.ToLower(os.Getenv("STORAGE_BACKEND"))
+	if backend == "" {
+		backend = "local"
+		if os.Getenv("SUPABASE_URL") != "" && os.Getenv("SUPABASE_SERVICE_KEY") != "" {
+			backend = "supabase"
+		}
+	}
+
+	switch backend {
+	case "local":
+		return NewLocalStore(bucket), nil
+	case "supabase":
+		// Returning the *SupabaseStore directly would wrap a nil pointer in a non-nil interface
+		store, err := NewSupabaseStore(bucket)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
+
+// ProfileBucket is the bucket holding profile photos
+func ProfileBucket() string {
+	return getEnv("PROFILE_BUCKET", "profiles")
+}
+
+// PrivateBucket is the bucket for files only admins may see, served through signed URLs
+func PrivateBucket() string {
+	return getEnv("PRIVATE_BUCKET", "private")
+}
+
+// MediaBucket is the bucket holding blog images
+func MediaBucket() string {
+	return getEnv("MEDIA_BUCKET", "media")
+}
+
+// PublicBuckets are the buckets whose objects anyone may fetch by URL
+func PublicBuckets() []string {
+	return []string{ProfileBucket(), MediaBucket()}
+}
+
+// RandomKeySegment is a random path segment for private object keys, so they
+// can't be derived from public URLs or IDs
+func RandomKeySegment() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ProfileOriginalsPrefix is where unwatermarked profile photos are kept in the private bucket
+const ProfileOriginalsPrefix = "profiles/"
+
+// VerificationPrefix is where verification photos are kept in the private bucket
+const VerificationPrefix = "verifications/"
+
+// UploadChunkPrefix is where chunks of resumable uploads wait in the private bucket
+const UploadChunkPrefix = "uploads/"
+
+// DeleteObjectsByURL deletes the objects behind public URLs, skipping URLs that
+// don't belong to store. It returns the number of objects deleted.
+func DeleteObjectsByURL(ctx context.Context, store ObjectStore, urls []string) int {
+	deleted := 0
+	for _, url := range urls {
+		key, ok := store.KeyFromURL(url)
+		if !ok {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			fmt.Printf("⚠️ Could not delete stored object %s: %v\n", key, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
+
+// cleanObjectKey rejects keys that could escape their bucket
+func cleanObjectKey(key string) (string, error) {
+	key = strings.TrimLeft(key, "/")
+	if key == "" {
+		return "", fmt.Errorf("object key is required")
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SupabaseStore keeps objects in a Supabase Storage bucket
+type SupabaseStore struct {
+	baseURL    string
+	serviceKey string
+	bucket     string
+	httpClient *http.Client
+}
+
+// NewSupabaseStore creates a store for bucket from SUPABASE_URL and SUPABASE_SERVICE_KEY
+func NewSupabaseStore(bucket string) (*SupabaseStore, error) {
+	baseURL := strings.TrimRight(os.Getenv("SUPABASE_URL"), "/")
+	serviceKey := os.Getenv("SUPABASE_SERVICE_KEY")
+
+	if baseURL == "" || serviceKey == "" {
+		return nil, fmt.Errorf("supabase configuration missing")
+	}
+
+	return &SupabaseStore{
+		baseURL:    baseURL,
+		serviceKey: serviceKey,
+		bucket:     bucket,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Minute, // Large uploads on slow links
+		},
+	}, nil
+}
+
+func (s *SupabaseStore) objectURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/object/%s/%s", s.baseURL, s.bucket, key)
+}
+
+func (s *SupabaseStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.objectURL(key), body)
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to Supabase: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("supabase upload failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (s *SupabaseStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download from Supabase: %w", err)
+	}
+
+	// Supabase answers 400 with a not_found body for missing objects
+	if resp.StatusCode == 404 || resp.StatusCode == 400 {
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("supabase download failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return resp.Body, nil
+}
+
+func (s *SupabaseStore) Delete(ctx context.Context, key string) error {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete from Supabase: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 && resp.StatusCode != 404 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("supabase delete failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (s *SupabaseStore) PublicURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/object/public/%s/%s", s.baseURL, s.bucket, strings.TrimLeft(key, "/"))
+}
+
+func (s *SupabaseStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	payload, _ := json.Marshal(map[string]int{"expiresIn": int(expiry.Seconds())})
+	url := fmt.Sprintf("%s/storage/v1/object/sign/%s/%s", s.baseURL, s.bucket, key)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create sign request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("supabase sign failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil || result.SignedURL == "" {
+		return "", fmt.Errorf("invalid sign response: %s", string(respBody))
+	}
+
+	// Supabase returns a path relative to /storage/v1
+	return s.baseURL + "/storage/v1" + result.SignedURL, nil
+}
+
+func (s *SupabaseStore) KeyFromURL(url string) (string, bool) {
+	prefix := fmt.Sprintf("%s/storage/v1/object/public/%s/", s.baseURL, s.bucket)
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key, err := cleanObjectKey(strings.TrimPrefix(url, prefix))
+	return key, err == nil
+}
+
+// supabaseListEntry is one row of the storage list API. Folders have no id.
+type supabaseListEntry struct {
+	Name      string    `json:"name"`
+	ID        *string   `json:"id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Metadata  *struct {
+		Size int64 `json:"size"`
+	} `json:"metadata"`
+}
+
+// List walks the bucket folder by folder, since the Supabase list API is not recursive
+func (s *SupabaseStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	// Start from the folder containing prefix and filter by the full prefix below
+	folder := ""
+	if slash := strings.LastIndex(prefix, "/"); slash >= 0 {
+		folder = prefix[:slash]
+	}
+
+	folders := []string{folder}
+	for len(folders) > 0 {
+		current := folders[0]
+		folders = folders[1:]
+
+		const pageSize = 1000
+		for offset := 0; ; offset += pageSize {
+			entries, err := s.listFolder(ctx, current, pageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				key := entry.Name
+				if current != "" {
+					key = current + "/" + entry.Name
+				}
+
+				if entry.ID == nil {
+					if strings.HasPrefix(key, prefix) || strings.HasPrefix(prefix, key+"/") {
+						folders = append(folders, key)
+					}
+					continue
+				}
+				if !strings.HasPrefix(key, prefix) {
+					continue
+				}
+
+				info := ObjectInfo{Key: key, UpdatedAt: entry.UpdatedAt}
+				if entry.Metadata != nil {
+					info.Size = entry.Metadata.Size
+				}
+				objects = append(objects, info)
+			}
+
+			if len(entries) < pageSize {
+				break
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+func (s *SupabaseStore) listFolder(ctx context.Context, folder string, limit, offset int) ([]supabaseListEntry, error) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"prefix": folder,
+		"limit":  limit,
+		"offset": offset,
+		"sortBy": map[string]string{"column": "name", "order": "asc"},
+	})
+	url := fmt.Sprintf("%s/storage/v1/object/list/%s", s.baseURL, s.bucket)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create list request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Supabase objects: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("supabase list failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var entries []supabaseListEntry
+	if err := json.Unmarshal(respBody, &entries); err != nil {
+		return nil, fmt.Errorf("invalid list response: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Watermark placements
+const (
+	WatermarkBottomRight = "bottom-right"
+	WatermarkBottomLeft  = "bottom-left"
+	WatermarkTopRight    = "top-right"
+	WatermarkTopLeft     = "top-left"
+	WatermarkCenter      = "center"
+)
+
+// WatermarkPositions lists every supported placement
+var WatermarkPositions = []string{
+	WatermarkBottomRight,
+	WatermarkBottomLeft,
+	WatermarkTopRight,
+	WatermarkTopLeft,
+	WatermarkCenter,
+}
+
+// IsWatermarkPosition reports whether position is a supported placement
+func IsWatermarkPosition(position string) bool {
+	for _, p := range WatermarkPositions {
+		if p == position {
+			return true
+		}
+	}
+	return false
+}
+
+// watermarkWidthRatio is the share of the photo width the text spans
+const watermarkWidthRatio = 0.35
+
+// Watermark returns a copy of img with text stamped at position. The text is
+// drawn white with a dark outline so it stays readable on any background.
+func Watermark(img image.Image, text string, position string) image.Image {
+	face := basicfont.Face7x13
+	textWidth := font.MeasureString(face, text).Ceil()
+	if textWidth == 0 {
+		return img
+	}
+
+	// Render the text once at the font's native size
+	const pad = 2
+	metrics := face.Metrics()
+	stamp := image.NewRGBA(image.Rect(0, 0, textWidth+2*pad, (metrics.Ascent+metrics.Descent).Ceil()+2*pad))
+	baseline := pad + metrics.Ascent.Ceil()
+
+	outline := image.NewUniform(color.NRGBA{0, 0, 0, 150})
+	for _, offset := range []image.Point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		drawer := font.Drawer{Dst: stamp, Src: outline, Face: face, Dot: fixed.P(pad+offset.X, baseline+offset.Y)}
+		drawer.DrawString(text)
+	}
+	drawer := font.Drawer{Dst: stamp, Src: image.NewUniform(color.NRGBA{255, 255, 255, 200}), Face: face, Dot: fixed.P(pad, baseline)}
+	drawer.DrawString(text)
+
+	// Scale it to a fixed share of the photo so it looks the same on every rendition
+	bounds := img.Bounds()
+	stampBounds := stamp.Bounds()
+	width := int(float64(bounds.Dx()) * watermarkWidthRatio)
+	if width < stampBounds.Dx() {
+		width = stampBounds.Dx()
+	}
+	if width > bounds.Dx() {
+		width = bounds.Dx()
+	}
+	height := stampBounds.Dy() * width / stampBounds.Dx()
+
+	margin := bounds.Dx() / 40
+	var x, y int
+	switch position {
+	case WatermarkBottomLeft:
+		x, y = margin, bounds.Dy()-height-margin
+	case WatermarkTopRight:
+		x, y = bounds.Dx()-width-margin, margin
+	case WatermarkTopLeft:
+		x, y = margin, margin
+	case WatermarkCenter:
+		x, y = (bounds.Dx()-width)/2, (bounds.Dy()-height)/2
+	default:
+		x, y = bounds.Dx()-width-margin, bounds.Dy()-height-margin
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	draw.ApproxBiLinear.Scale(dst, image.Rect(x, y, x+width, y+height), stamp, stampBounds, draw.Over, nil)
+	return dst
+}